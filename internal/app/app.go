@@ -119,17 +119,18 @@ func init() {
 	rootCmd.Flags().Bool("json", false, "show result as JSON")
 	rootCmd.Flags().Bool("warnings", false, "show only warnings")
 	rootCmd.Flags().Bool("no-color", false, "disable colorized output")
-	rootCmd.Flags().Bool("env", false, "show environment variables for the process")
+	rootCmd.Flags().Bool("env", false, "show environment variables for the process and where each came from")
 	rootCmd.Flags().Bool("verbose", false, "show extended process information")
 	rootCmd.Flags().BoolP("exact", "x", false, "use exact name matching (no substring search)")
 	rootCmd.Flags().BoolP("interactive", "i", false, "interactive mode (TUI)")
-
+	rootCmd.Flags().StringSlice("ignore-warning", nil, "suppress warnings by ID (repeatable, e.g. WITR-ROOT)")
+	addRedactFlags(rootCmd)
 }
 
 func runApp(cmd *cobra.Command, args []string) error {
 	interactiveFlag, _ := cmd.Flags().GetBool("interactive")
 	if interactiveFlag {
-		return runInteractive()
+		return runInteractive(cmd)
 	}
 
 	envFlag, _ := cmd.Flags().GetBool("env")
@@ -138,7 +139,7 @@ func runApp(cmd *cobra.Command, args []string) error {
 	fileFlag, _ := cmd.Flags().GetString("file")
 	// Default to interactive mode if no arguments or relevant flags are provided
 	if !envFlag && pidFlag == "" && portFlag == "" && fileFlag == "" && len(args) == 0 {
-		return runInteractive()
+		return runInteractive(cmd)
 	}
 	shortFlag, _ := cmd.Flags().GetBool("short")
 	treeFlag, _ := cmd.Flags().GetBool("tree")
@@ -147,9 +148,11 @@ func runApp(cmd *cobra.Command, args []string) error {
 	noColorFlag, _ := cmd.Flags().GetBool("no-color")
 	verboseFlag, _ := cmd.Flags().GetBool("verbose")
 	exactFlag, _ := cmd.Flags().GetBool("exact")
+	ignoreWarnings, _ := cmd.Flags().GetStringSlice("ignore-warning")
 
 	outw := cmd.OutOrStdout()
 	outp := output.NewPrinter(outw)
+	rd := outputRedactor(cmd)
 
 	if envFlag {
 		var t model.Target
@@ -183,6 +186,7 @@ func runApp(cmd *cobra.Command, args []string) error {
 					command = proc.Command
 					cmdline = proc.Cmdline
 				}
+				cmdline = rd.Text(cmdline)
 				if !noColorFlag {
 					outp.Printf("[%d] %s%s%s (%spid %d%s)\n    %s\n",
 						i+1, output.ColorGreen, command, output.ColorReset,
@@ -202,10 +206,19 @@ func runApp(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("error: %v", err)
 		}
 
+		var parent *model.Process
+		if procInfo.PPID > 0 {
+			if pp, err := procpkg.ReadProcess(procInfo.PPID); err == nil {
+				parent = &pp
+			}
+		}
+
 		resEnv := model.Result{
-			Process:  procInfo,
-			Ancestry: []model.Process{procInfo},
+			Process:    procInfo,
+			Ancestry:   []model.Process{procInfo},
+			EnvOrigins: procpkg.EnvOrigins(procInfo, parent),
 		}
+		resEnv = rd.Result(resEnv)
 
 		if jsonFlag {
 			importJSON, err := output.ToEnvJSON(resEnv)
@@ -281,6 +294,7 @@ func runApp(cmd *cobra.Command, args []string) error {
 				command = proc.Command
 				cmdline = proc.Cmdline
 			}
+			cmdline = rd.Text(cmdline)
 			if !noColorFlag {
 				outp.Printf("[%d] %s%s%s (%spid %d%s)\n    %s\n",
 					i+1, output.ColorGreen, command, output.ColorReset,
@@ -313,10 +327,12 @@ func runApp(cmd *cobra.Command, args []string) error {
 
 	// Refactored to use shared pipeline.AnalyzePID
 	res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
-		PID:     pid,
-		Verbose: verboseFlag,
-		Tree:    treeFlag,
-		Target:  t,
+		PID:            pid,
+		Verbose:        verboseFlag,
+		Tree:           treeFlag,
+		Target:         t,
+		IgnoreWarnings: ignoreWarnings,
+		Packages:       verboseFlag || jsonFlag,
 	})
 
 	if err != nil {
@@ -340,6 +356,8 @@ func runApp(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	res = rd.Result(res)
+
 	if jsonFlag {
 		var importJSON string
 		var err error
@@ -372,12 +390,12 @@ func runApp(cmd *cobra.Command, args []string) error {
 
 func Root() *cobra.Command { return rootCmd }
 
-func runInteractive() error {
+func runInteractive(cmd *cobra.Command) error {
 	v := version
 	if v == "v0.0.0-dev" {
 		v = ""
 	}
-	return tui.Start(v)
+	return tui.Start(v, outputRedactor(cmd))
 }
 
 func SetVersionBuildCommitString(Version string, Commit string, BuildDate string) {
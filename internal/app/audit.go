@@ -0,0 +1,71 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/pipeline"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Run system-wide checks for suspicious processes",
+	Long: `Run system-wide checks for suspicious processes.
+
+Checks:
+  network-shell   shells and interpreters with stdin/stdout/stderr on a
+                  network socket (reverse or bind shells)
+
+Exits with status 1 when any check has findings.`,
+	Example: `
+  # Audit all processes (run as root to see every process's fds)
+  sudo witr audit
+
+  # Machine-readable output
+  witr audit --json
+`,
+	Args: cobra.NoArgs,
+	RunE: runAudit,
+}
+
+func init() {
+	auditCmd.Flags().Bool("json", false, "show result as JSON")
+	auditCmd.Flags().Bool("no-color", false, "disable colorized output")
+	addRedactFlags(auditCmd)
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+	noColorFlag, _ := cmd.Flags().GetBool("no-color")
+
+	checks, err := pipeline.Audit()
+	if err != nil {
+		return fmt.Errorf("audit failed: %w", err)
+	}
+	checks = outputRedactor(cmd).AuditChecks(checks)
+
+	outw := cmd.OutOrStdout()
+	if jsonFlag {
+		data, err := output.ToAuditJSON(checks)
+		if err != nil {
+			return fmt.Errorf("failed to generate json output: %w", err)
+		}
+		fmt.Fprintln(outw, data)
+	} else {
+		output.RenderAudit(outw, checks, !noColorFlag)
+	}
+
+	findings := 0
+	for _, check := range checks {
+		findings += len(check.Findings)
+	}
+	if findings > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("audit found %d suspicious process(es)", findings)
+	}
+	return nil
+}
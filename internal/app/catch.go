@@ -0,0 +1,138 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/pipeline"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+var catchCmd = &cobra.Command{
+	Use:   "catch <name>",
+	Short: "Wait for a short-lived process to start and explain it",
+	Long: `Wait for a process matching <name> to start, snapshot it and its full
+ancestry at exec time, and explain it once it exits (with exit code and
+lifetime) or after --wait.
+
+On Linux, events come from the proc connector (netlink CN_PROC), which
+needs root or CAP_NET_ADMIN. Without it, and on other platforms, witr
+falls back to polling the process list, which misses processes that live
+shorter than --interval and cannot report exit codes.
+
+Names match like the root command: a case-insensitive substring of the
+process name or command line, or with --exact, the exact name or argument.`,
+	Example: `
+  # Explain the next run of a cron job
+  sudo witr catch backup.sh
+
+  # Keep catching a crashlooping binary
+  sudo witr catch --count 0 myapp
+
+  # Give up after a minute
+  witr catch --timeout 1m post-receive
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCatch,
+}
+
+func init() {
+	catchCmd.Flags().BoolP("exact", "x", false, "use exact name matching (no substring search)")
+	catchCmd.Flags().Int("count", 1, "stop after this many captures (0 = until interrupted)")
+	catchCmd.Flags().Duration("timeout", 0, "give up after this long (0 = wait forever)")
+	catchCmd.Flags().Duration("wait", 2*time.Second, "how long to follow a caught process for its exit")
+	catchCmd.Flags().Duration("interval", 10*time.Millisecond, "process list scan interval when polling")
+	catchCmd.Flags().Bool("poll", false, "poll the process list instead of using the proc connector")
+	catchCmd.Flags().Bool("json", false, "show each capture as JSON")
+	catchCmd.Flags().Bool("no-color", false, "disable colorized output")
+	catchCmd.Flags().Bool("verbose", false, "show extended process information")
+	catchCmd.Flags().StringSlice("ignore-warning", nil, "suppress warnings by ID (repeatable, e.g. WITR-ROOT)")
+	addRedactFlags(catchCmd)
+	rootCmd.AddCommand(catchCmd)
+}
+
+func runCatch(cmd *cobra.Command, args []string) error {
+	exactFlag, _ := cmd.Flags().GetBool("exact")
+	countFlag, _ := cmd.Flags().GetInt("count")
+	timeoutFlag, _ := cmd.Flags().GetDuration("timeout")
+	waitFlag, _ := cmd.Flags().GetDuration("wait")
+	intervalFlag, _ := cmd.Flags().GetDuration("interval")
+	pollFlag, _ := cmd.Flags().GetBool("poll")
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+	noColorFlag, _ := cmd.Flags().GetBool("no-color")
+	verboseFlag, _ := cmd.Flags().GetBool("verbose")
+	ignoreWarnings, _ := cmd.Flags().GetStringSlice("ignore-warning")
+
+	if countFlag < 0 {
+		return fmt.Errorf("--count must not be negative")
+	}
+	if intervalFlag <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	if timeoutFlag > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeoutFlag)
+		defer cancel()
+	}
+
+	name := args[0]
+	outw := cmd.OutOrStdout()
+	errw := cmd.ErrOrStderr()
+	rd := outputRedactor(cmd)
+	captures := 0
+	var renderErr error
+
+	err := pipeline.Catch(ctx, pipeline.CatchConfig{
+		Name:     name,
+		Exact:    exactFlag,
+		Count:    countFlag,
+		Wait:     waitFlag,
+		Interval: intervalFlag,
+		Poll:     pollFlag,
+		Analyze: pipeline.AnalyzeConfig{
+			Verbose:        verboseFlag,
+			Target:         model.Target{Type: model.TargetName, Value: name},
+			IgnoreWarnings: ignoreWarnings,
+			Packages:       verboseFlag || jsonFlag,
+		},
+	}, func(method string) {
+		fmt.Fprintf(errw, "Waiting for %q (%s)...\n", name, method)
+	}, func(res model.Result) {
+		res = rd.Result(res)
+		if captures > 0 && !jsonFlag {
+			fmt.Fprintln(outw)
+		}
+		captures++
+		if jsonFlag {
+			data, err := output.ToJSON(res)
+			if err != nil {
+				renderErr = fmt.Errorf("failed to generate json output: %w", err)
+				return
+			}
+			fmt.Fprintln(outw, data)
+			return
+		}
+		output.RenderStandard(outw, res, !noColorFlag, verboseFlag)
+	})
+	if err != nil {
+		return fmt.Errorf("catch failed: %w", err)
+	}
+	if renderErr != nil {
+		return renderErr
+	}
+	if captures == 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("no process matching %q started", name)
+	}
+	return nil
+}
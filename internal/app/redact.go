@@ -0,0 +1,47 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pranshuparmar/witr/internal/redact"
+	"github.com/pranshuparmar/witr/internal/rules"
+	"github.com/spf13/cobra"
+)
+
+// addRedactFlags registers --redact and --no-redact on a command that
+// prints process details.
+func addRedactFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("redact", false, "mask secrets in env values and command lines (default when output is not a terminal)")
+	cmd.Flags().Bool("no-redact", false, "never mask secrets, even when output is not a terminal")
+	cmd.MarkFlagsMutuallyExclusive("redact", "no-redact")
+}
+
+// outputRedactor returns the redactor for cmd's output, or nil when
+// redaction is off. It is on by default when stdout is not a terminal, so
+// piped and redirected output is safe to share.
+func outputRedactor(cmd *cobra.Command) *redact.Redactor {
+	on, _ := cmd.Flags().GetBool("redact")
+	off, _ := cmd.Flags().GetBool("no-redact")
+	if off || (!on && isTerminal(cmd.OutOrStdout())) {
+		return nil
+	}
+	set, err := rules.Load(rules.DefaultPaths())
+	if err != nil {
+		// The valid rules still apply; say which ones were left out
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: some rules were not loaded (check with 'witr rules check'):\n%v\n", err)
+	}
+	return redact.New(set.RedactKeys, set.RedactPatterns)
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
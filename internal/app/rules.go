@@ -0,0 +1,103 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/internal/rules"
+	"github.com/spf13/cobra"
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Manage custom source detectors and warning rules",
+	Long: `witr reads extra source detectors and warning rules from
+/etc/witr/rules.d/*.yaml and ~/.config/witr/rules.yaml.`,
+}
+
+var rulesCheckCmd = &cobra.Command{
+	Use:   "check [file...]",
+	Short: "Validate rules files",
+	Long:  "Validate rules files. Without arguments, the default rules locations are checked.",
+	Example: `
+  # Validate the installed rules
+  witr rules check
+
+  # Validate a file before installing it
+  witr rules check ./acme.yaml
+`,
+	RunE: runRulesCheck,
+}
+
+func init() {
+	rulesCmd.AddCommand(rulesCheckCmd)
+	rootCmd.AddCommand(rulesCmd)
+}
+
+func runRulesCheck(cmd *cobra.Command, args []string) error {
+	paths := args
+	explicit := len(args) > 0
+	if !explicit {
+		paths = rules.DefaultPaths()
+	}
+
+	outp := output.NewPrinter(cmd.OutOrStdout())
+	checked, failed := 0, 0
+	for _, path := range paths {
+		f, err := rules.LoadFile(path)
+		if errors.Is(err, os.ErrNotExist) && !explicit {
+			continue
+		}
+		checked++
+		if err != nil {
+			failed++
+			outp.Printf("FAIL %s\n", path)
+			for _, e := range unwrapJoined(err) {
+				outp.Printf("  %s\n", e)
+			}
+			continue
+		}
+		summary := plural(len(f.Detectors), "detector") + ", " + plural(len(f.Warnings), "warning")
+		if len(f.Ignore) > 0 {
+			summary += ", " + plural(len(f.Ignore), "ignore rule")
+		}
+		if n := f.Redact.Count(); n > 0 {
+			summary += ", " + plural(n, "redact rule")
+		}
+		outp.Printf("ok   %s (%s)\n", path, summary)
+	}
+
+	if checked == 0 {
+		outp.Println("No rules files found. Looked in:")
+		outp.Printf("  %s\n", filepath.Join(rules.SystemDir, "*.yaml"))
+		for _, path := range paths {
+			outp.Printf("  %s\n", path)
+		}
+		return nil
+	}
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d rules files are invalid", failed, checked)
+	}
+	return nil
+}
+
+// unwrapJoined splits an errors.Join result back into its parts.
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
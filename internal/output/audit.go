@@ -0,0 +1,76 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// RenderAudit prints the result of `witr audit`, one block per check.
+func RenderAudit(w io.Writer, checks []model.AuditCheck, colorEnabled bool) {
+	out := NewPrinter(w)
+
+	for i, check := range checks {
+		if i > 0 {
+			out.Println()
+		}
+		if colorEnabled {
+			out.Printf("%sCheck%s       : %s\n", ColorBlue, ColorReset, check.ID)
+		} else {
+			out.Printf("Check       : %s\n", check.ID)
+		}
+		out.Printf("              %s\n", check.Description)
+
+		switch {
+		case !check.Supported:
+			out.Println("Result      : not supported on this platform")
+			continue
+		case len(check.Findings) == 0:
+			if colorEnabled {
+				out.Printf("Result      : %sno findings%s (%d processes scanned)\n", ColorGreen, ColorReset, check.Scanned)
+			} else {
+				out.Printf("Result      : no findings (%d processes scanned)\n", check.Scanned)
+			}
+			printUnchecked(out, check)
+			continue
+		}
+
+		if colorEnabled {
+			out.Printf("%sFindings%s    : %d (%d processes scanned)\n", ColorRed, ColorReset, len(check.Findings), check.Scanned)
+		} else {
+			out.Printf("Findings    : %d (%d processes scanned)\n", len(check.Findings), check.Scanned)
+		}
+		printUnchecked(out, check)
+		for _, f := range check.Findings {
+			printWarning(out, f.Warning, colorEnabled, true)
+			chain := make([]string, 0, len(f.Ancestry))
+			for _, p := range f.Ancestry {
+				chain = append(chain, chainLabel(p)+" (pid "+strconv.Itoa(p.PID)+")")
+			}
+			if len(chain) > 0 {
+				out.Printf("      ancestry: %s\n", SanitizeTerminal(strings.Join(chain, " → ")))
+			}
+		}
+	}
+}
+
+// printUnchecked notes processes the check could not inspect, so a clean
+// result is not read as covering them.
+func printUnchecked(out Printer, check model.AuditCheck) {
+	if check.Unchecked == 0 {
+		return
+	}
+	out.Printf("Note        : %d processes were not checked; the socket tables of their network namespace (e.g. a container) could not be read\n", check.Unchecked)
+}
+
+// ToAuditJSON returns the audit checks as JSON.
+func ToAuditJSON(checks []model.AuditCheck) (string, error) {
+	data, err := json.MarshalIndent(checks, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -55,6 +55,33 @@ func RenderDockerFallback(w io.Writer, portValue string, match *model.DockerPort
 		}
 	}
 
+	// Restart policy
+	if match.RestartPolicy != "" {
+		if colorEnabled {
+			out.Printf("%sRestart%s     : %s\n", ColorBlue, ColorReset, match.RestartPolicy)
+		} else {
+			out.Printf("Restart     : %s\n", match.RestartPolicy)
+		}
+	}
+
+	// Health
+	if match.Health != "" {
+		if colorEnabled {
+			out.Printf("%sHealth%s      : %s\n", ColorBlue, ColorReset, match.Health)
+		} else {
+			out.Printf("Health      : %s\n", match.Health)
+		}
+	}
+
+	// Restarts
+	if match.RestartCount > 0 {
+		if colorEnabled {
+			out.Printf("%sRestarts%s    : %d\n", ColorBlue, ColorReset, match.RestartCount)
+		} else {
+			out.Printf("Restarts    : %d\n", match.RestartCount)
+		}
+	}
+
 	// Why It Exists
 	if colorEnabled {
 		out.Printf("\n%sWhy It Exists%s :\n  ", ColorMagenta, ColorReset)
@@ -107,6 +134,9 @@ func DockerFallbackToJSON(portValue string, match *model.DockerPortMatch) (strin
 		Ports          string `json:",omitempty"`
 		ComposeProject string `json:",omitempty"`
 		ComposeService string `json:",omitempty"`
+		RestartPolicy  string `json:",omitempty"`
+		Health         string `json:",omitempty"`
+		RestartCount   int    `json:",omitempty"`
 		Source         string
 		Note           string
 	}
@@ -119,6 +149,9 @@ func DockerFallbackToJSON(portValue string, match *model.DockerPortMatch) (strin
 		Ports:          match.Ports,
 		ComposeProject: match.ComposeProject,
 		ComposeService: match.ComposeService,
+		RestartPolicy:  match.RestartPolicy,
+		Health:         match.Health,
+		RestartCount:   match.RestartCount,
 		Source:         dockerSourceLabel(match),
 		Note:           "The owning process is not visible in this environment. This is common when Docker Desktop runs in a separate namespace (e.g., WSL2 distro, macOS VM).",
 	}
@@ -0,0 +1,45 @@
+package output
+
+import (
+	"path/filepath"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// entrypointLabel formats an entrypoint for the Entrypoint line.
+func entrypointLabel(ep *model.Entrypoint) string {
+	label := ep.Target
+	switch ep.Kind {
+	case "module":
+		label = "-m " + ep.Target
+	case "class":
+		label = "class " + ep.Target
+	}
+	if ep.AppName != "" {
+		label += " (" + ep.AppName + ")"
+	}
+	if ep.Shebang != "" {
+		label += " [#!" + ep.Shebang + "]"
+	}
+	return label
+}
+
+// chainLabel names a process in ancestry chains, adding the entrypoint of
+// runtime processes so "java" reads as "java orders.jar".
+func chainLabel(p model.Process) string {
+	name := p.Command
+	if name == "" && p.Cmdline != "" {
+		name = p.Cmdline
+	}
+	if ep := p.Entrypoint; ep != nil {
+		switch ep.Kind {
+		case "jar", "script":
+			name += " " + filepath.Base(ep.Target)
+		case "module":
+			name += " -m " + ep.Target
+		default:
+			name += " " + ep.Target
+		}
+	}
+	return SanitizeTerminal(name)
+}
@@ -35,7 +35,12 @@ func RenderEnvOnly(w io.Writer, r model.Result, colorEnabled bool) {
 	}
 
 	p.Printf("%sCommand%s     : %s\n", colorGreenEnv, colorResetEnv, r.Process.Cmdline)
-	if len(r.Process.Env) > 0 {
+	if len(r.EnvOrigins) > 0 {
+		p.Printf("%sEnvironment%s :\n", colorBlueEnv, colorResetEnv)
+		for _, v := range r.EnvOrigins {
+			p.Printf("  %s=%s  %s# %s%s\n", v.Name, v.Value, colorBoldEnv, envOriginLabel(v), colorResetEnv)
+		}
+	} else if len(r.Process.Env) > 0 {
 		p.Printf("%sEnvironment%s :\n", colorBlueEnv, colorResetEnv)
 		for _, env := range r.Process.Env {
 			p.Printf("  %s\n", env)
@@ -44,3 +49,20 @@ func RenderEnvOnly(w io.Writer, r model.Result, colorEnabled bool) {
 		p.Printf("%sEnvironment%s : %sNo environment variables found.%s\n", colorBlueEnv, colorResetEnv, colorRedEnv, colorResetEnv)
 	}
 }
+
+// envOriginLabel says where a variable came from, e.g. "inherited from
+// bash (pid 812)" or "set by systemd EnvironmentFile=/etc/default/app".
+func envOriginLabel(v model.EnvVar) string {
+	switch v.Origin {
+	case model.EnvInherited:
+		return "inherited from " + v.Detail
+	case model.EnvLauncher:
+		if v.Changed {
+			return "changed by " + v.Detail
+		}
+		return "added by " + v.Detail
+	case model.EnvSource:
+		return "set by " + v.Detail
+	}
+	return "origin unknown"
+}
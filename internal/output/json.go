@@ -17,12 +17,13 @@ func ToJSON(r model.Result) (string, error) {
 type shortProcess struct {
 	PID     int
 	Command string
+	Exited  bool `json:",omitempty"`
 }
 
 func ToShortJSON(r model.Result) (string, error) {
 	ancestry := make([]shortProcess, len(r.Ancestry))
 	for i, p := range r.Ancestry {
-		ancestry[i] = shortProcess{PID: p.PID, Command: p.Command}
+		ancestry[i] = shortProcess{PID: p.PID, Command: p.Command, Exited: p.Exited}
 	}
 	data, err := json.MarshalIndent(ancestry, "", "  ")
 	if err != nil {
@@ -42,7 +43,7 @@ func ToTreeJSON(r model.Result) (string, error) {
 	}
 
 	for i, p := range r.Ancestry {
-		res.Ancestry[i] = shortProcess{PID: p.PID, Command: p.Command}
+		res.Ancestry[i] = shortProcess{PID: p.PID, Command: p.Command, Exited: p.Exited}
 	}
 
 	if len(r.Children) > 0 {
@@ -64,7 +65,9 @@ func ToWarningsJSON(r model.Result) (string, error) {
 		PID      int
 		Process  string
 		Command  string
-		Warnings []string
+		Warnings []model.Warning
+		// Suppressed counts warnings silenced by --ignore-warning or the allowlist
+		Suppressed int `json:",omitempty"`
 	}
 
 	procName := "unknown"
@@ -81,14 +84,15 @@ func ToWarningsJSON(r model.Result) (string, error) {
 
 	warnings := r.Warnings
 	if warnings == nil {
-		warnings = []string{}
+		warnings = []model.Warning{}
 	}
 
 	res := warningResult{
-		PID:      r.Process.PID,
-		Process:  procName,
-		Command:  cmdLine,
-		Warnings: warnings,
+		PID:        r.Process.PID,
+		Process:    procName,
+		Command:    cmdLine,
+		Warnings:   warnings,
+		Suppressed: r.SuppressedWarnings,
 	}
 
 	data, err := json.MarshalIndent(res, "", "  ")
@@ -104,6 +108,8 @@ func ToEnvJSON(r model.Result) (string, error) {
 		Process string
 		Command string
 		Env     []string
+		// Variables carries each variable with its origin
+		Variables []model.EnvVar `json:",omitempty"`
 	}
 
 	procName := "unknown"
@@ -114,10 +120,11 @@ func ToEnvJSON(r model.Result) (string, error) {
 	}
 
 	res := envResult{
-		PID:     r.Process.PID,
-		Process: procName,
-		Command: r.Process.Cmdline,
-		Env:     r.Process.Env,
+		PID:       r.Process.PID,
+		Process:   procName,
+		Command:   r.Process.Cmdline,
+		Env:       r.Process.Env,
+		Variables: r.EnvOrigins,
 	}
 
 	data, err := json.MarshalIndent(res, "", "  ")
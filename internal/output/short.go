@@ -23,9 +23,9 @@ func RenderShort(w io.Writer, r model.Result, colorEnabled bool) {
 			if i == len(r.Ancestry)-1 {
 				nameColor = ColorGreen
 			}
-			p.Printf("%s%s%s (%spid %d%s)", nameColor, proc.Command, ColorReset, ColorBold, proc.PID, ColorReset)
+			p.Printf("%s%s%s (%spid %d%s%s)", nameColor, chainLabel(proc), ColorReset, ColorBold, proc.PID, ColorReset, exitedNote(proc))
 		} else {
-			p.Printf("%s (pid %d)", proc.Command, proc.PID)
+			p.Printf("%s (pid %d%s)", chainLabel(proc), proc.PID, exitedNote(proc))
 		}
 	}
 	p.Println()
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/pranshuparmar/witr/pkg/model"
@@ -18,10 +20,38 @@ const MaxDisplayItems = 10
 // formatDetailLabel formats a detail key into a padded label for display
 func formatDetailLabel(key string) string {
 	labels := map[string]string{
-		"type":      "              Type",
-		"plist":     "              Plist",
-		"triggers":  "              Trigger",
-		"keepalive": "              KeepAlive",
+		"type":        "              Type",
+		"plist":       "              Plist",
+		"triggers":    "              Trigger",
+		"keepalive":   "              KeepAlive",
+		"session":     "              Session",
+		"window":      "              Window",
+		"pane":        "              Pane",
+		"socket":      "              Socket",
+		"owner":       "              Owner",
+		"attach":      "              Attach",
+		"namespace":   "              Namespace",
+		"pod":         "              Pod",
+		"container":   "              Container",
+		"qos":         "              QoS Class",
+		"restarts":    "              Restarts",
+		"image":       "              Image",
+		"compose":     "              Compose",
+		"restart":     "              Restart",
+		"health":      "              Health",
+		"machine":     "              Machine",
+		"monitor":     "              Monitor",
+		"package":     "              Package",
+		"app":         "              App",
+		"revision":    "              Revision",
+		"branch":      "              Branch",
+		"runtime":     "              Runtime",
+		"confinement": "              Confinement",
+		"repository":  "              Repository",
+		"workflow":    "              Workflow",
+		"job":         "              Job",
+		"run":         "              Run",
+		"runner":      "              Runner",
 	}
 	if label, ok := labels[key]; ok {
 		return label
@@ -61,19 +91,43 @@ func RenderWarnings(w io.Writer, r model.Result, colorEnabled bool) {
 		} else {
 			out.Println("Warnings    : No warnings.")
 		}
+		printSuppressed(out, r.SuppressedWarnings)
 		return
 	}
 
 	if colorEnabled {
 		out.Printf("%sWarnings%s    :\n", ColorRed, ColorReset)
-		for _, w := range r.Warnings {
-			out.Printf("  • %s\n", SanitizeTerminal(w))
-		}
 	} else {
 		out.Println("Warnings    :")
-		for _, w := range r.Warnings {
-			out.Printf("  • %s\n", SanitizeTerminal(w))
-		}
+	}
+	for _, w := range r.Warnings {
+		printWarning(out, w, colorEnabled, true)
+	}
+	printSuppressed(out, r.SuppressedWarnings)
+}
+
+// printWarning prints one warning bullet. With detail, the ID, severity and
+// evidence are included so the warning can be looked up or suppressed.
+func printWarning(out Printer, w model.Warning, colorEnabled bool, detail bool) {
+	if !detail {
+		out.Printf("  • %s\n", SanitizeTerminal(w.Message))
+		return
+	}
+	if colorEnabled {
+		out.Printf("  • %s %s[%s, %s]%s\n", SanitizeTerminal(w.Message), ColorBold, w.ID, w.Severity, ColorReset)
+	} else {
+		out.Printf("  • %s [%s, %s]\n", SanitizeTerminal(w.Message), w.ID, w.Severity)
+	}
+	for _, e := range w.Evidence {
+		out.Printf("      %s\n", SanitizeTerminal(e))
+	}
+}
+
+func printSuppressed(out Printer, n int) {
+	if n == 1 {
+		out.Println("  (1 warning suppressed)")
+	} else if n > 1 {
+		out.Printf("  (%d warnings suppressed)\n", n)
 	}
 }
 
@@ -115,13 +169,14 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 			out.Printf(" [%s]", health)
 		}
 	}
-	// Forked status: only display if forked
-	if proc.Forked == "forked" {
+	// Forked status: only display if forked, adopted or daemonized
+	switch proc.Forked {
+	case "forked", "adopted", "daemonized":
 		forkColor := ColorDimYellow
 		if colorEnabled {
-			out.Printf(" %s{forked}%s", forkColor, ColorReset)
+			out.Printf(" %s{%s}%s", forkColor, proc.Forked, ColorReset)
 		} else {
-			out.Printf(" {forked}")
+			out.Printf(" {%s}", proc.Forked)
 		}
 	}
 	out.Println("")
@@ -150,6 +205,32 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		}
 	}
 
+	// Package
+	if pkg := proc.Package; pkg != nil {
+		label := "not owned by any package (" + pkg.Manager + ")"
+		if pkg.Owned {
+			label = pkg.Name
+			if pkg.Version != "" {
+				label += " " + pkg.Version
+			}
+			if pkg.Origin != "" {
+				label += " from " + pkg.Origin
+			} else if pkg.Vendor != "" {
+				label += ", vendor " + pkg.Vendor
+			}
+			label += " (" + pkg.Manager + ")"
+			if pkg.Integrity == model.IntegrityModified {
+				label += " [modified]"
+			}
+		}
+		label = SanitizeTerminal(label)
+		if colorEnabled {
+			out.Printf("%sPackage%s     : %s\n", ColorBlue, ColorReset, label)
+		} else {
+			out.Printf("Package     : %s\n", label)
+		}
+	}
+
 	if proc.Cmdline != "" {
 		if colorEnabled {
 			out.Printf("%sCommand%s     : %s\n", ColorBlue, ColorReset, proc.Cmdline)
@@ -163,70 +244,72 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 			out.Printf("Command     : %s\n", proc.Command)
 		}
 	}
-	// Format as: 2 days ago (Mon 2025-02-02 11:42:10 +0530)
-	startedAt := proc.StartedAt
-	now := time.Now()
-	dur := now.Sub(startedAt)
-	var rel string
-	switch {
-	case dur.Hours() >= 48:
-		days := int(dur.Hours()) / 24
-		rel = fmt.Sprintf("%d days ago", days)
-	case dur.Hours() >= 24:
-		rel = "1 day ago"
-	case dur.Hours() >= 2:
-		hours := int(dur.Hours())
-		rel = fmt.Sprintf("%d hours ago", hours)
-	case dur.Minutes() >= 60:
-		rel = "1 hour ago"
-	default:
-		mins := int(dur.Minutes())
-		if mins > 0 {
-			rel = fmt.Sprintf("%d min ago", mins)
+	if proc.Entrypoint != nil {
+		entrypoint := SanitizeTerminal(entrypointLabel(proc.Entrypoint))
+		if colorEnabled {
+			out.Printf("%sEntrypoint%s  : %s\n", ColorBlue, ColorReset, entrypoint)
 		} else {
-			rel = "just now"
+			out.Printf("Entrypoint  : %s\n", entrypoint)
 		}
 	}
+	// Format as: 2 days ago (Mon 2025-02-02 11:42:10 +0530)
+	startedAt := proc.StartedAt
+	rel := relativeAge(time.Since(startedAt))
 	dtStr := startedAt.Format("Mon 2006-01-02 15:04:05 -07:00")
 	if colorEnabled {
 		out.Printf("%sStarted%s     : %s (%s)\n", ColorMagenta, ColorReset, rel, dtStr)
 	} else {
 		out.Printf("Started     : %s (%s)\n", rel, dtStr)
 	}
+	if c := r.Capture; c != nil {
+		if colorEnabled {
+			out.Printf("%sCaught%s      : %s\n", ColorMagenta, ColorReset, captureSummary(c))
+		} else {
+			out.Printf("Caught      : %s\n", captureSummary(c))
+		}
+	}
 
 	// Why It Exists (short chain)
 	if colorEnabled {
 		out.Printf("\n%sWhy It Exists%s :\n  ", ColorMagenta, ColorReset)
 		for i, p := range r.Ancestry {
-			name := p.Command
-			if name == "" && p.Cmdline != "" {
-				name = p.Cmdline
-			}
-			name = SanitizeTerminal(name)
+			name := chainLabel(p)
 
 			nameColor := ansiString("")
 			if i == len(r.Ancestry)-1 {
 				nameColor = ColorGreen
 			}
-			out.Printf("%s%s%s (%spid %d%s)", nameColor, name, ColorReset, ColorBold, p.PID, ColorReset)
+			out.Printf("%s%s%s (%spid %d%s%s)", nameColor, name, ColorReset, ColorBold, p.PID, ColorReset, exitedNote(p))
 			if i < len(r.Ancestry)-1 {
 				out.Printf(" %s\u2192%s ", ColorMagenta, ColorReset)
 			}
 		}
+		if proc.Adoption != nil {
+			out.Printf("\n  %s\u21b3 %s%s", ColorMagenta, SanitizeTerminal(adoptionNote(proc.Adoption)), ColorReset)
+			if verbose {
+				for _, e := range proc.Adoption.Evidence {
+					out.Printf("\n    %s", SanitizeTerminal(e))
+				}
+			}
+		}
 		out.Print("\n\n")
 	} else {
 		out.Printf("\nWhy It Exists :\n  ")
 		for i, p := range r.Ancestry {
-			name := p.Command
-			if name == "" && p.Cmdline != "" {
-				name = p.Cmdline
-			}
-			name = SanitizeTerminal(name)
-			out.Printf("%s (pid %d)", name, p.PID)
+			name := chainLabel(p)
+			out.Printf("%s (pid %d%s)", name, p.PID, exitedNote(p))
 			if i < len(r.Ancestry)-1 {
 				out.Printf(" \u2192 ")
 			}
 		}
+		if proc.Adoption != nil {
+			out.Printf("\n  \u21b3 %s", SanitizeTerminal(adoptionNote(proc.Adoption)))
+			if verbose {
+				for _, e := range proc.Adoption.Evidence {
+					out.Printf("\n    %s", SanitizeTerminal(e))
+				}
+			}
+		}
 		out.Print("\n\n")
 	}
 
@@ -266,6 +349,8 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 			label = "Registry Key"
 		case model.SourceBsdRc:
 			label = "Rc Script"
+		case model.SourceContainer:
+			label = "Config"
 		}
 
 		var pad string
@@ -285,7 +370,7 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 	// Source details (launchd triggers, plist path, etc.)
 	if len(r.Source.Details) > 0 {
 		// Display in consistent order
-		detailKeys := []string{"type", "plist", "triggers", "keepalive"}
+		detailKeys := []string{"type", "plist", "triggers", "keepalive", "session", "window", "pane", "socket", "owner", "attach", "namespace", "pod", "container", "qos", "restarts", "image", "compose", "restart", "health", "machine", "monitor", "package", "app", "revision", "branch", "runtime", "confinement", "repository", "workflow", "job", "run", "runner"}
 		for _, key := range detailKeys {
 			if val, ok := r.Source.Details[key]; ok {
 				label := formatDetailLabel(key)
@@ -298,6 +383,38 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		}
 	}
 
+	// Why the source was chosen, and what else matched
+	if verbose && r.Source.Type != model.SourceUnknown {
+		for i, e := range r.Source.Evidence {
+			switch {
+			case i > 0:
+				out.Printf("              %s\n", SanitizeTerminal(e))
+			case colorEnabled:
+				out.Printf("%sEvidence%s    : %s\n", ColorBold, ColorReset, SanitizeTerminal(e))
+			default:
+				out.Printf("Evidence    : %s\n", SanitizeTerminal(e))
+			}
+		}
+		if r.Source.Confidence > 0 {
+			if colorEnabled {
+				out.Printf("%sConfidence%s  : %d%%\n", ColorBold, ColorReset, r.Source.Confidence)
+			} else {
+				out.Printf("Confidence  : %d%%\n", r.Source.Confidence)
+			}
+		}
+		if len(r.SourceAlternatives) > 0 {
+			alts := make([]string, 0, len(r.SourceAlternatives))
+			for _, alt := range r.SourceAlternatives {
+				alts = append(alts, sourceCandidateLabel(alt))
+			}
+			if colorEnabled {
+				out.Printf("%sAlternatives%s: %s\n", ColorBold, ColorReset, SanitizeTerminal(strings.Join(alts, ", ")))
+			} else {
+				out.Printf("Alternatives: %s\n", SanitizeTerminal(strings.Join(alts, ", ")))
+			}
+		}
+	}
+
 	// Context group
 	if colorEnabled {
 		if proc.WorkingDir != "" && proc.WorkingDir != "unknown" {
@@ -360,18 +477,39 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		}
 	}
 
+	// Connection counts by state
+	if summary := connectionSummary(proc.Connections); summary != "" {
+		if colorEnabled {
+			out.Printf("%sConnections%s : %s\n", ColorGreen, ColorReset, summary)
+		} else {
+			out.Printf("Connections : %s\n", summary)
+		}
+	}
+
+	// Who is failing to reap a defunct target
+	if lines := zombieLines(r.Zombie); len(lines) > 0 {
+		if colorEnabled {
+			out.Printf("\n%sZombie%s      :\n", ColorRed, ColorReset)
+		} else {
+			out.Printf("\nZombie      :\n")
+		}
+		for _, line := range lines {
+			out.Printf("  %s\n", SanitizeTerminal(line))
+		}
+	}
+
 	// Warnings
 	if len(r.Warnings) > 0 {
 		if colorEnabled {
 			out.Printf("\n%sWarnings%s    :\n", ColorRed, ColorReset)
-			for _, w := range r.Warnings {
-				out.Printf("  • %s\n", SanitizeTerminal(w))
-			}
 		} else {
 			out.Println("\nWarnings    :")
-			for _, w := range r.Warnings {
-				out.Printf("  • %s\n", SanitizeTerminal(w))
-			}
+		}
+		for _, w := range r.Warnings {
+			printWarning(out, w, colorEnabled, verbose)
+		}
+		if verbose {
+			printSuppressed(out, r.SuppressedWarnings)
 		}
 	}
 
@@ -454,6 +592,30 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 			}
 		}
 
+		// Executable provenance
+		if exe := proc.Executable; exe != nil {
+			if colorEnabled {
+				out.Printf("\n%sExecutable%s:\n", ColorGreen, ColorReset)
+			} else {
+				out.Printf("\nExecutable:\n")
+			}
+			for _, line := range executableLines(exe, proc.StartedAt) {
+				out.Printf("  %s\n", SanitizeTerminal(line))
+			}
+		}
+
+		// Privilege and confinement posture
+		if lines := securityLines(proc.Security); len(lines) > 0 {
+			if colorEnabled {
+				out.Printf("\n%sSecurity%s:\n", ColorGreen, ColorReset)
+			} else {
+				out.Printf("\nSecurity:\n")
+			}
+			for _, line := range lines {
+				out.Printf("  %s\n", SanitizeTerminal(line))
+			}
+		}
+
 		// File context (open files, locks)
 		if r.FileContext != nil {
 			if r.FileContext.OpenFiles > 0 && r.FileContext.FileLimit == 0 {
@@ -559,6 +721,18 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 			}
 		}
 
+		// Full connection inventory
+		if len(proc.Connections) > 0 {
+			if colorEnabled {
+				out.Printf("\n%sConnections%s:\n", ColorGreen, ColorReset)
+			} else {
+				out.Printf("\nConnections:\n")
+			}
+			for _, line := range connectionLines(proc.Connections) {
+				out.Printf("  %s\n", SanitizeTerminal(line))
+			}
+		}
+
 		// Socket state (for port queries)
 		if r.SocketInfo != nil {
 			state := SanitizeTerminal(r.SocketInfo.State)
@@ -599,3 +773,256 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		}
 	}
 }
+
+// exitedNote describes an ancestor recovered from process accounting, as
+// ", exited 2 hours ago, user alice".
+func exitedNote(p model.Process) string {
+	if !p.Exited {
+		return ""
+	}
+	note := ", exited"
+	if !p.ExitedAt.IsZero() {
+		note += " " + relativeAge(time.Since(p.ExitedAt))
+	}
+	if p.User != "" {
+		note += ", user " + SanitizeTerminal(p.User)
+	}
+	return note
+}
+
+// adoptionNote explains a reparented process in one line.
+func adoptionNote(a *model.AdoptionInfo) string {
+	adopter := "init"
+	if a.Subreaper {
+		adopter = "subreaper"
+	}
+	return fmt.Sprintf("original parent exited; adopted by %s %s (pid %d)", adopter, a.AdopterCommand, a.AdopterPID)
+}
+
+// captureSummary describes how a caught process ended, e.g.
+// "exited with code 1 after 23ms (proc connector)".
+func captureSummary(c *model.CaptureInfo) string {
+	var what string
+	switch {
+	case !c.Exited:
+		what = "still running, exec'd at " + c.ExecAt.Format("15:04:05.000")
+	case !c.ExitKnown:
+		what = "exited after ~" + formatLifetime(c.Lifetime) + ", exit code unknown"
+	case c.Signal != 0:
+		what = fmt.Sprintf("killed by signal %d (%s) after %s", c.Signal, syscall.Signal(c.Signal), formatLifetime(c.Lifetime))
+	default:
+		what = fmt.Sprintf("exited with code %d after %s", c.ExitCode, formatLifetime(c.Lifetime))
+	}
+	return what + " (" + c.Method + ")"
+}
+
+func formatLifetime(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
+
+// relativeAge formats a duration as "3 hours ago".
+func relativeAge(dur time.Duration) string {
+	switch {
+	case dur.Hours() >= 48:
+		return fmt.Sprintf("%d days ago", int(dur.Hours())/24)
+	case dur.Hours() >= 24:
+		return "1 day ago"
+	case dur.Hours() >= 2:
+		return fmt.Sprintf("%d hours ago", int(dur.Hours()))
+	case dur.Minutes() >= 60:
+		return "1 hour ago"
+	}
+	if mins := int(dur.Minutes()); mins > 0 {
+		return fmt.Sprintf("%d min ago", mins)
+	}
+	return "just now"
+}
+
+// zombieLines formats the parent diagnosis for a defunct target.
+func zombieLines(z *model.ZombieInfo) []string {
+	if z == nil {
+		return nil
+	}
+	parent := fmt.Sprintf("Parent   : %s (pid %d)", z.ParentCommand, z.ParentPID)
+	if src := z.ParentSource; src.Type != model.SourceUnknown && src.Type != "" {
+		if src.Name != "" && src.Name != string(src.Type) {
+			parent += " via " + src.Name + " (" + string(src.Type) + ")"
+		} else {
+			parent += " via " + string(src.Type)
+		}
+	}
+	state := "State    : " + z.ParentState
+	if z.ParentWaitChannel != "" {
+		state += " in " + z.ParentWaitChannel
+	}
+	unreaped := fmt.Sprintf("Unreaped : %d zombie child", z.ZombieCount)
+	if z.ZombieCount != 1 {
+		unreaped += "ren"
+	}
+	if !z.OldestStartedAt.IsZero() {
+		unreaped += fmt.Sprintf(", oldest pid %d started %s", z.OldestZombiePID, relativeAge(time.Since(z.OldestStartedAt)))
+	}
+	lines := []string{parent}
+	if z.ParentState != "" {
+		lines = append(lines, state)
+	}
+	return append(lines, unreaped, "Fix      : "+z.Remedy)
+}
+
+// sourceCandidateLabel formats a source alternative as "name (type, 60%)".
+func sourceCandidateLabel(src model.Source) string {
+	name := string(src.Type)
+	if src.Name != "" && src.Name != name {
+		name = src.Name + " (" + name + ", " + strconv.Itoa(src.Confidence) + "%)"
+	} else {
+		name += " (" + strconv.Itoa(src.Confidence) + "%)"
+	}
+	return name
+}
+
+// connectionSummary counts sockets by state, most common first, as
+// "14 (9 ESTABLISHED, 3 CLOSE_WAIT, 2 LISTEN)".
+func connectionSummary(conns []model.Socket) string {
+	if len(conns) == 0 {
+		return ""
+	}
+	counts := make(map[string]int)
+	var states []string
+	for _, c := range conns {
+		if counts[c.State] == 0 {
+			states = append(states, c.State)
+		}
+		counts[c.State]++
+	}
+	sort.SliceStable(states, func(i, j int) bool {
+		if counts[states[i]] != counts[states[j]] {
+			return counts[states[i]] > counts[states[j]]
+		}
+		return states[i] < states[j]
+	})
+	parts := make([]string, len(states))
+	for i, state := range states {
+		parts[i] = fmt.Sprintf("%d %s", counts[state], state)
+	}
+	return fmt.Sprintf("%d (%s)", len(conns), strings.Join(parts, ", "))
+}
+
+// connectionLines formats the verbose connection list, one socket per
+// line with its queue sizes in bytes.
+func connectionLines(conns []model.Socket) []string {
+	lines := make([]string, 0, len(conns))
+	for _, c := range conns {
+		endpoints := net.JoinHostPort(c.Address, strconv.Itoa(c.Port))
+		if c.RemotePort != 0 {
+			endpoints += " -> " + net.JoinHostPort(c.RemoteAddress, strconv.Itoa(c.RemotePort))
+		}
+		lines = append(lines, fmt.Sprintf("%-4s %-45s %-11s rx %d tx %d", c.Protocol, endpoints, c.State, c.RxQueue, c.TxQueue))
+	}
+	return lines
+}
+
+// executableLines formats the verbose executable block. The mtime is only
+// compared with startedAt when the start time is known.
+func executableLines(exe *model.ExecutableInfo, startedAt time.Time) []string {
+	lines := []string{"Path     : " + exe.Path}
+	lines = append(lines, fmt.Sprintf("Size     : %.1f MB", float64(exe.Size)/(1024*1024)))
+	modified := exe.ModTime.Format("2006-01-02 15:04:05 -07:00")
+	switch {
+	case exe.ModifiedAfterStart:
+		modified += " (after process start)"
+	case !startedAt.IsZero():
+		modified += " (before process start)"
+	}
+	lines = append(lines, "Modified : "+modified)
+	if exe.SHA256 != "" {
+		lines = append(lines, "SHA-256  : "+exe.SHA256)
+	}
+	if exe.BuildID != "" {
+		lines = append(lines, "Build ID : "+exe.BuildID)
+	}
+	if exe.Linkage != "" {
+		linkage := exe.Linkage
+		if exe.Interpreter != "" {
+			linkage += " (" + exe.Interpreter + ")"
+		}
+		lines = append(lines, "Linkage  : "+linkage)
+	}
+	if g := exe.Go; g != nil {
+		module := g.MainModule
+		if g.MainVersion != "" {
+			module += " " + g.MainVersion
+		}
+		lines = append(lines, "Go       : "+g.GoVersion+", "+module)
+		if g.VCSRevision != "" {
+			revision := g.VCSRevision
+			if g.VCSTime != "" {
+				revision += " (" + g.VCSTime + ")"
+			}
+			if g.VCSModified {
+				revision += " +dirty"
+			}
+			lines = append(lines, "Revision : "+revision)
+		}
+	}
+	return lines
+}
+
+// securityLines formats the verbose security block. Platforms without
+// capabilities or an LSM only report setuid/setgid executables.
+func securityLines(sec *model.SecurityInfo) []string {
+	if sec == nil {
+		return nil
+	}
+	var lines []string
+	if sec.CapBounding != nil {
+		lines = append(lines,
+			"Effective  : "+capabilityList(sec.CapEffective, sec.CapBounding),
+			"Permitted  : "+capabilityList(sec.CapPermitted, sec.CapBounding),
+			"Bounding   : "+capabilityList(sec.CapBounding, nil),
+		)
+		if len(sec.CapAmbient) > 0 {
+			lines = append(lines, "Ambient    : "+capabilityList(sec.CapAmbient, sec.CapBounding))
+		}
+		noNewPrivs := "no"
+		if sec.NoNewPrivs {
+			noNewPrivs = "yes"
+		}
+		lines = append(lines, "NoNewPrivs : "+noNewPrivs)
+	}
+	if sec.Seccomp != "" {
+		lines = append(lines, "Seccomp    : "+sec.Seccomp)
+	}
+	if sec.LSM != "" {
+		lines = append(lines, "LSM        : "+sec.LSM+" "+sec.LSMLabel)
+	}
+	if sec.Setuid || sec.Setgid {
+		bits := "setuid"
+		if sec.Setuid && sec.Setgid {
+			bits = "setuid, setgid"
+		} else if sec.Setgid {
+			bits = "setgid"
+		}
+		lines = append(lines, "Exe Mode   : "+sec.ExeMode+" ("+bits+")")
+	}
+	return lines
+}
+
+// capabilityList keeps long capability sets readable: a set equal to the
+// bounding set is named as such, and large sets are summarized by count.
+func capabilityList(caps, bounding []string) string {
+	switch {
+	case len(caps) == 0:
+		return "none"
+	case len(bounding) > 0 && slices.Equal(caps, bounding):
+		return "all in bounding set"
+	case len(caps) > 6:
+		return fmt.Sprintf("%d capabilities", len(caps))
+	}
+	return strings.Join(caps, ", ")
+}
@@ -25,9 +25,9 @@ func PrintTree(w io.Writer, chain []model.Process, children []model.Process, col
 			if i == len(chain)-1 {
 				cmdColor = ColorGreen
 			}
-			p.Printf("%s%s%s (%spid %d%s)\n", cmdColor, proc.Command, ColorReset, ColorBold, proc.PID, ColorReset)
+			p.Printf("%s%s%s (%spid %d%s%s)\n", cmdColor, chainLabel(proc), ColorReset, ColorBold, proc.PID, ColorReset, exitedNote(proc))
 		} else {
-			p.Printf("%s (pid %d)\n", proc.Command, proc.PID)
+			p.Printf("%s (pid %d%s)\n", chainLabel(proc), proc.PID, exitedNote(proc))
 		}
 	}
 
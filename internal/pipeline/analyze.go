@@ -1,6 +1,9 @@
 package pipeline
 
 import (
+	"fmt"
+
+	"github.com/pranshuparmar/witr/internal/pkgdb"
 	procpkg "github.com/pranshuparmar/witr/internal/proc"
 	"github.com/pranshuparmar/witr/internal/source"
 	"github.com/pranshuparmar/witr/pkg/model"
@@ -11,6 +14,12 @@ type AnalyzeConfig struct {
 	Verbose bool
 	Tree    bool
 	Target  model.Target
+	// IgnoreWarnings lists warning IDs to drop from the result
+	IgnoreWarnings []string
+	// Packages looks up the owning package, its repository and its
+	// recorded digests, which can mean scanning every dpkg file list and
+	// apt's package lists; set for --verbose and --json
+	Packages bool
 }
 
 func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
@@ -18,8 +27,36 @@ func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
 	if err != nil {
 		return model.Result{}, err
 	}
+	return AnalyzeAncestry(cfg, procpkg.ReconstructAncestry(ancestry))
+}
+
+// AnalyzeAncestry analyzes an ancestry that was already resolved, with the
+// target last. Reads of processes that have since exited are best effort.
+func AnalyzeAncestry(cfg AnalyzeConfig, ancestry []model.Process) (model.Result, error) {
+	if len(ancestry) == 0 {
+		return model.Result{}, fmt.Errorf("no process ancestry found")
+	}
+	cfg.PID = ancestry[len(ancestry)-1].PID
+
+	// Reverse-shell check: stdio of shells and interpreters in the chain
+	var stdioPIDs []int
+	for _, p := range ancestry {
+		if !p.Exited && source.IsShellOrInterpreter(p.Command) {
+			stdioPIDs = append(stdioPIDs, p.PID)
+		}
+	}
+	if socks, _ := procpkg.ReadStdioSockets(stdioPIDs); len(socks) > 0 {
+		for i := range ancestry {
+			ancestry[i].StdioSockets = socks[ancestry[i].PID]
+		}
+	}
 
-	src := source.Detect(ancestry)
+	candidates := source.DetectAll(ancestry)
+	src := model.Source{Type: model.SourceUnknown}
+	if len(candidates) > 0 {
+		src = candidates[0]
+		candidates = candidates[1:]
+	}
 
 	var proc model.Process
 	resolvedTarget := "unknown"
@@ -28,6 +65,20 @@ func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
 		resolvedTarget = proc.Command
 	}
 
+	// The host package databases say nothing about files in a container
+	if cfg.Packages && proc.Exe != "" && !proc.ExeDeleted && procpkg.SharesRoot(proc.PID) {
+		proc.Package = pkgdb.Owner(proc.Exe)
+		pkgdb.Origin(proc.Package)
+		pkgdb.Verify(proc.Package, proc.Exe)
+		ancestry[len(ancestry)-1] = proc
+	}
+
+	if len(ancestry) > 0 {
+		proc.Security = procpkg.ReadSecurityInfo(proc.PID, proc.Exe)
+		proc.Connections = procpkg.ReadConnections(proc.PID)
+		ancestry[len(ancestry)-1] = proc
+	}
+
 	if cfg.Verbose && len(ancestry) > 0 {
 		memInfo, ioStats, fileDescs, fdCount, fdLimit, children, threadCount, err := procpkg.ReadExtendedInfo(cfg.PID)
 		if err == nil {
@@ -42,6 +93,11 @@ func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
 		}
 	}
 
+	if cfg.Verbose && proc.Exe != "" {
+		proc.Executable = procpkg.ReadExecutableInfo(proc.PID, proc.Exe, proc.StartedAt)
+		ancestry[len(ancestry)-1] = proc
+	}
+
 	var resCtx *model.ResourceContext
 	var fileCtx *model.FileContext
 	if cfg.Verbose {
@@ -63,17 +119,22 @@ func AnalyzePID(cfg AnalyzeConfig) (model.Result, error) {
 		}
 	}
 
+	warnings, suppressed := source.SuppressWarnings(source.Warnings(ancestry, src), cfg.IgnoreWarnings, src, proc)
+
 	res := model.Result{
-		Target:          cfg.Target,
-		ResolvedTarget:  resolvedTarget,
-		Process:         proc,
-		RestartCount:    restartCount,
-		Ancestry:        ancestry,
-		Source:          src,
-		Warnings:        source.Warnings(ancestry),
-		ResourceContext: resCtx,
-		FileContext:     fileCtx,
-		Children:        childProcesses,
+		Target:             cfg.Target,
+		ResolvedTarget:     resolvedTarget,
+		Process:            proc,
+		RestartCount:       restartCount,
+		Ancestry:           ancestry,
+		Source:             src,
+		SourceAlternatives: candidates,
+		Warnings:           warnings,
+		SuppressedWarnings: suppressed,
+		ResourceContext:    resCtx,
+		FileContext:        fileCtx,
+		Children:           childProcesses,
+		Zombie:             diagnoseZombie(ancestry),
 	}
 
 	return res, nil
@@ -0,0 +1,59 @@
+package pipeline
+
+import (
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Audit runs the system-wide checks behind `witr audit`.
+func Audit() ([]model.AuditCheck, error) {
+	procs, err := procpkg.ListProcesses()
+	if err != nil {
+		return nil, err
+	}
+	return []model.AuditCheck{auditNetworkShells(procs)}, nil
+}
+
+// auditNetworkShells looks for shells and interpreters whose standard
+// streams are network sockets.
+func auditNetworkShells(procs []model.Process) model.AuditCheck {
+	check := model.AuditCheck{
+		ID:          "network-shell",
+		Description: "Shells and interpreters with stdin/stdout/stderr on a network socket",
+		Supported:   procpkg.StdioSocketsSupported,
+	}
+	if !check.Supported {
+		return check
+	}
+
+	var pids []int
+	for _, p := range procs {
+		if source.IsShellOrInterpreter(p.Command) {
+			pids = append(pids, p.PID)
+		}
+	}
+	socks, unchecked := procpkg.ReadStdioSockets(pids)
+	check.Scanned = len(pids) - len(unchecked)
+	check.Unchecked = len(unchecked)
+	for _, pid := range pids {
+		if len(socks[pid]) == 0 {
+			continue
+		}
+		ancestry, err := procpkg.ResolveAncestry(pid)
+		if err != nil || len(ancestry) == 0 {
+			continue
+		}
+		proc := ancestry[len(ancestry)-1]
+		proc.StdioSockets = socks[pid]
+		ancestry[len(ancestry)-1] = proc
+		if warning, ok := source.NetworkShellWarning(proc, ancestry); ok {
+			check.Findings = append(check.Findings, model.AuditFinding{
+				Process:  proc,
+				Ancestry: ancestry,
+				Warning:  warning,
+			})
+		}
+	}
+	return check
+}
@@ -0,0 +1,162 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+type CatchConfig struct {
+	Name  string
+	Exact bool
+	// Count stops after this many captures (0 = until ctx is done)
+	Count int
+	// Wait is how long to follow a caught process for its exit
+	Wait time.Duration
+	// Interval between /proc scans when polling
+	Interval time.Duration
+	// Poll skips the proc connector
+	Poll    bool
+	Analyze AnalyzeConfig
+}
+
+// Overridable for tests
+var (
+	watchProcesses  = procpkg.WatchProcesses
+	resolveAncestry = procpkg.ResolveAncestry
+	getCmdline      = procpkg.GetCmdline
+)
+
+type caught struct {
+	ancestry []model.Process
+	execAt   time.Time
+}
+
+// Catch waits for new processes matching cfg.Name. Each match has its
+// ancestry snapshotted at exec time, is followed until it exits or
+// cfg.Wait passes, then is analyzed and passed to found. started is called
+// with the capture method once events are flowing.
+func Catch(ctx context.Context, cfg CatchConfig, started func(method string), found func(model.Result)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, method, err := watchProcesses(ctx, cfg.Interval, cfg.Poll)
+	if err != nil {
+		return err
+	}
+	if started != nil {
+		started(method)
+	}
+
+	self := os.Getpid()
+	parents := make(map[int]int)
+	pending := make(map[int]*caught)
+	reported := 0
+
+	report := func(pid int, c *caught, exit *procpkg.ProcEvent) {
+		delete(pending, pid)
+		res, err := AnalyzeAncestry(cfg.Analyze, c.ancestry)
+		if err != nil {
+			return
+		}
+		capture := &model.CaptureInfo{Method: method, ExecAt: c.execAt}
+		if exit != nil {
+			capture.Exited = true
+			capture.ExitedAt = exit.Time
+			capture.Lifetime = exit.Time.Sub(c.execAt)
+			capture.ExitKnown = exit.ExitKnown
+			capture.ExitCode = exit.ExitCode
+			capture.Signal = exit.Signal
+		}
+		res.Capture = capture
+		found(res)
+		reported++
+	}
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for cfg.Count == 0 || reported < cfg.Count {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			switch ev.Kind {
+			case procpkg.ProcFork:
+				parents[ev.PID] = ev.PPID
+			case procpkg.ProcExec:
+				if ev.PID == self || pending[ev.PID] != nil {
+					continue
+				}
+				cmdline := getCmdline(ev.PID)
+				if !matchesName(ev.Command, cmdline, cfg.Name, cfg.Exact) {
+					continue
+				}
+				if ev.PPID == 0 {
+					ev.PPID = parents[ev.PID]
+				}
+				pending[ev.PID] = &caught{ancestry: snapshotAncestry(ev, cmdline), execAt: ev.Time}
+			case procpkg.ProcExit:
+				delete(parents, ev.PID)
+				if c := pending[ev.PID]; c != nil {
+					report(ev.PID, c, &ev)
+				}
+			}
+		case now := <-ticker.C:
+			for pid, c := range pending {
+				if now.Sub(c.execAt) >= cfg.Wait {
+					report(pid, c, nil)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// snapshotAncestry reads the caught process and its ancestors right away.
+// If it is already gone or defunct, what the event carried stands in for it
+// and the chain continues from its parent.
+func snapshotAncestry(ev procpkg.ProcEvent, cmdline string) []model.Process {
+	if chain, err := resolveAncestry(ev.PID); err == nil {
+		target := chain[len(chain)-1]
+		if target.Health != "zombie" {
+			return chain
+		}
+		if ev.PPID == 0 {
+			ev.PPID = target.PPID
+		}
+	}
+	p := model.Process{PID: ev.PID, PPID: ev.PPID, Command: ev.Command, Cmdline: cmdline, StartedAt: ev.Time, Forked: "unknown"}
+	var chain []model.Process
+	if ev.PPID > 0 {
+		chain, _ = resolveAncestry(ev.PPID)
+	}
+	return append(chain, p)
+}
+
+// matchesName mirrors name targeting: case-insensitive substring of comm or
+// command line, or with exact, the comm or any argument equal to name.
+func matchesName(comm, cmdline, name string, exact bool) bool {
+	comm, cmdline, name = strings.ToLower(comm), strings.ToLower(cmdline), strings.ToLower(name)
+	if strings.Contains(comm, "grep") || strings.Contains(cmdline, "grep") {
+		return false
+	}
+	if exact {
+		if comm == name {
+			return true
+		}
+		for _, arg := range strings.Fields(cmdline) {
+			if arg == name {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.Contains(comm, name) || strings.Contains(cmdline, name)
+}
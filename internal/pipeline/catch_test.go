@@ -0,0 +1,193 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// fakeWatch replays events and keeps the channel open until ctx is done.
+func fakeWatch(evs ...procpkg.ProcEvent) func(context.Context, time.Duration, bool) (<-chan procpkg.ProcEvent, string, error) {
+	return func(ctx context.Context, _ time.Duration, _ bool) (<-chan procpkg.ProcEvent, string, error) {
+		ch := make(chan procpkg.ProcEvent)
+		go func() {
+			for _, ev := range evs {
+				select {
+				case ch <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		return ch, "fake", nil
+	}
+}
+
+func TestCatch(t *testing.T) {
+	defer func(w func(context.Context, time.Duration, bool) (<-chan procpkg.ProcEvent, string, error), r func(int) ([]model.Process, error), c func(int) string) {
+		watchProcesses, resolveAncestry, getCmdline = w, r, c
+	}(watchProcesses, resolveAncestry, getCmdline)
+
+	shell := model.Process{PID: 50, PPID: 1, Command: "crond"}
+	running := map[int]model.Process{
+		201: {PID: 201, PPID: 50, Command: "backup.sh", Cmdline: "/bin/sh /opt/backup.sh"},
+		202: {PID: 202, PPID: 50, Command: "backup.sh", Health: "zombie"},
+		300: {PID: 300, PPID: 50, Command: "rsync"},
+	}
+	resolveAncestry = func(pid int) ([]model.Process, error) {
+		if pid == 50 {
+			return []model.Process{shell}, nil
+		}
+		if p, ok := running[pid]; ok {
+			return []model.Process{shell, p}, nil
+		}
+		return nil, errors.New("gone")
+	}
+	getCmdline = func(pid int) string { return running[pid].Cmdline }
+
+	start := time.Now()
+	tests := []struct {
+		name    string
+		events  []procpkg.ProcEvent
+		count   int
+		wantPID []int
+		check   func(t *testing.T, res []model.Result)
+	}{
+		{
+			name: "exit code and lifetime",
+			events: []procpkg.ProcEvent{
+				{Kind: procpkg.ProcExec, PID: 300, Command: "rsync", Time: start},
+				{Kind: procpkg.ProcExec, PID: 201, Command: "backup.sh", Time: start},
+				{Kind: procpkg.ProcExit, PID: 201, ExitKnown: true, ExitCode: 2, Time: start.Add(40 * time.Millisecond)},
+			},
+			count:   1,
+			wantPID: []int{201},
+			check: func(t *testing.T, res []model.Result) {
+				c := res[0].Capture
+				if c == nil || !c.Exited || !c.ExitKnown || c.ExitCode != 2 || c.Method != "fake" {
+					t.Fatalf("Capture = %+v", c)
+				}
+				if c.Lifetime != 40*time.Millisecond {
+					t.Errorf("Lifetime = %v, want 40ms", c.Lifetime)
+				}
+				if len(res[0].Ancestry) != 2 || res[0].Ancestry[0].PID != 50 {
+					t.Errorf("Ancestry = %+v, want crond → backup.sh", res[0].Ancestry)
+				}
+			},
+		},
+		{
+			name: "gone before snapshot",
+			events: []procpkg.ProcEvent{
+				{Kind: procpkg.ProcFork, PID: 203, PPID: 50},
+				{Kind: procpkg.ProcExec, PID: 203, Command: "backup.sh", Time: start},
+				{Kind: procpkg.ProcExit, PID: 203, ExitKnown: true, Signal: 9, Time: start},
+			},
+			count:   1,
+			wantPID: []int{203},
+			check: func(t *testing.T, res []model.Result) {
+				chain := res[0].Ancestry
+				if len(chain) != 2 || chain[0].PID != 50 || chain[1].Command != "backup.sh" {
+					t.Errorf("Ancestry = %+v, want crond → backup.sh from the event", chain)
+				}
+				if res[0].Capture.Signal != 9 {
+					t.Errorf("Signal = %d, want 9", res[0].Capture.Signal)
+				}
+			},
+		},
+		{
+			name: "defunct at snapshot",
+			events: []procpkg.ProcEvent{
+				{Kind: procpkg.ProcExec, PID: 202, Command: "backup.sh", Time: start},
+				{Kind: procpkg.ProcExit, PID: 202, ExitKnown: true, Time: start},
+			},
+			count:   1,
+			wantPID: []int{202},
+			check: func(t *testing.T, res []model.Result) {
+				if h := res[0].Process.Health; h == "zombie" {
+					t.Errorf("Health = %q, want the zombie state of the snapshot dropped", h)
+				}
+			},
+		},
+		{
+			name: "still running after wait",
+			events: []procpkg.ProcEvent{
+				{Kind: procpkg.ProcExec, PID: 201, Command: "backup.sh", Time: start},
+			},
+			count:   1,
+			wantPID: []int{201},
+			check: func(t *testing.T, res []model.Result) {
+				if c := res[0].Capture; c == nil || c.Exited {
+					t.Errorf("Capture = %+v, want still running", c)
+				}
+			},
+		},
+		{
+			name: "count",
+			events: []procpkg.ProcEvent{
+				{Kind: procpkg.ProcExec, PID: 201, Command: "backup.sh", Time: start},
+				{Kind: procpkg.ProcExit, PID: 201, ExitKnown: true, Time: start},
+				{Kind: procpkg.ProcExec, PID: 204, Command: "backup.sh", Time: start},
+				{Kind: procpkg.ProcExit, PID: 204, ExitKnown: true, Time: start},
+				{Kind: procpkg.ProcExec, PID: 205, Command: "backup.sh", Time: start},
+				{Kind: procpkg.ProcExit, PID: 205, ExitKnown: true, Time: start},
+			},
+			count:   2,
+			wantPID: []int{201, 204},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watchProcesses = fakeWatch(tt.events...)
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			var res []model.Result
+			method := ""
+			err := Catch(ctx, CatchConfig{Name: "backup", Count: tt.count, Wait: 20 * time.Millisecond},
+				func(m string) { method = m },
+				func(r model.Result) { res = append(res, r) })
+			if err != nil {
+				t.Fatalf("Catch: %v", err)
+			}
+			if method != "fake" {
+				t.Errorf("started method = %q, want fake", method)
+			}
+			if len(res) != len(tt.wantPID) {
+				t.Fatalf("got %d captures, want %d", len(res), len(tt.wantPID))
+			}
+			for i, pid := range tt.wantPID {
+				if res[i].Process.PID != pid {
+					t.Errorf("capture %d pid = %d, want %d", i, res[i].Process.PID, pid)
+				}
+			}
+			if tt.check != nil {
+				tt.check(t, res)
+			}
+		})
+	}
+}
+
+func TestMatchesName(t *testing.T) {
+	tests := []struct {
+		comm, cmdline, name string
+		exact               bool
+		want                bool
+	}{
+		{"backup.sh", "/bin/sh /opt/backup.sh", "backup", false, true},
+		{"sh", "/bin/sh /opt/Backup.sh", "BACKUP", false, true},
+		{"rsync", "rsync -a /src /dst", "backup", false, false},
+		{"grep", "grep backup", "backup", false, false},
+		{"sh", "sh -c true", "sh", true, true},
+		{"bash", "/bin/bash /opt/backup.sh", "backup", true, false},
+		{"bash", "/bin/bash backup", "backup", true, true},
+	}
+	for _, tt := range tests {
+		if got := matchesName(tt.comm, tt.cmdline, tt.name, tt.exact); got != tt.want {
+			t.Errorf("matchesName(%q, %q, %q, %v) = %v, want %v", tt.comm, tt.cmdline, tt.name, tt.exact, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Overridable for tests
+var (
+	zombieChildren = procpkg.ZombieChildren
+	readWaitState  = procpkg.ReadWaitState
+	pidNamespace   = procpkg.PIDNamespace
+)
+
+var parentStates = map[string]string{
+	"R": "running",
+	"S": "sleeping",
+	"I": "idle",
+	"D": "blocked (uninterruptible wait)",
+	"U": "blocked (uninterruptible wait)",
+	"T": "stopped",
+	"t": "stopped by debugger",
+	"Z": "zombie",
+}
+
+// diagnoseZombie identifies the parent responsible for reaping a defunct
+// target and what will clear it. It returns nil unless the target is a
+// zombie with a known parent. The parent is the live process with the
+// target's PPID, not an exited ancestor spliced into the chain; when the
+// chain was rebuilt through exited ancestors it is resolved afresh.
+func diagnoseZombie(ancestry []model.Process) *model.ZombieInfo {
+	if len(ancestry) < 2 || ancestry[len(ancestry)-1].Health != "zombie" {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+	var parents []model.Process
+	if i := source.LiveParentIndex(ancestry, target); i >= 0 {
+		parents = ancestry[:i+1]
+	} else if chain, err := resolveAncestry(target.PPID); err == nil && len(chain) > 0 {
+		parents = chain
+	} else {
+		return nil
+	}
+	parent := parents[len(parents)-1]
+
+	info := &model.ZombieInfo{
+		ParentPID:       parent.PID,
+		ParentCommand:   parent.Command,
+		ParentSource:    source.Detect(parents),
+		ZombieCount:     1,
+		OldestZombiePID: target.PID,
+		OldestStartedAt: target.StartedAt,
+	}
+
+	if zombies := zombieChildren(parent.PID); len(zombies) > 0 {
+		info.ZombieCount = len(zombies)
+		info.OldestZombiePID = zombies[0].PID
+		info.OldestStartedAt = zombies[0].StartedAt
+	}
+
+	state, wchan := readWaitState(parent.PID)
+	info.ParentState = parentStates[state]
+	if info.ParentState == "" {
+		info.ParentState = state
+	}
+	info.ParentWaitChannel = wchan
+
+	info.Restart = restartCommand(info.ParentSource)
+	info.Remedy = zombieRemedy(state, parent, isContainerInit(parents), info)
+	return info
+}
+
+// isContainerInit reports whether the last process of chain is the first
+// one in its PID namespace while the chain's root is in another: the init
+// of a container. Ancestry uses host PIDs, so that init is not PID 1 here.
+func isContainerInit(chain []model.Process) bool {
+	last := chain[len(chain)-1]
+	ns := pidNamespace(last.PID)
+	if ns == "" || ns == pidNamespace(chain[0].PID) {
+		return false
+	}
+	for _, p := range chain[:len(chain)-1] {
+		if !p.Exited && pidNamespace(p.PID) == ns {
+			return false
+		}
+	}
+	return true
+}
+
+// restartCommand returns the command that restarts the service owning a
+// process, for service managers where it is unambiguous. The systemd
+// source is named "systemd"; the unit it resolved from the cgroup is in
+// its details.
+func restartCommand(src model.Source) string {
+	switch src.Type {
+	case model.SourceSystemd:
+		// Scopes (login sessions, apps) cannot be restarted
+		if unit := src.Details["unit"]; strings.HasSuffix(unit, ".service") {
+			return "systemctl restart " + unit
+		}
+	case model.SourceLaunchd:
+		// Without a job label the source falls back to the name "launchd"
+		if src.Name != "" && src.Name != "launchd" {
+			return "launchctl kickstart -k system/" + src.Name
+		}
+	}
+	return ""
+}
+
+func zombieRemedy(state string, parent model.Process, containerInit bool, info *model.ZombieInfo) string {
+	switch state {
+	case "T", "t":
+		return fmt.Sprintf("Parent is stopped and cannot reap; resume it with kill -CONT %d", parent.PID)
+	case "D", "U":
+		msg := "Parent is blocked in the kernel"
+		if info.ParentWaitChannel != "" {
+			msg += " (" + info.ParentWaitChannel + ")"
+		}
+		return msg + " and will reap once that wait completes; check the storage or mount it is waiting on"
+	case "Z":
+		return "Parent is itself defunct; once it is reaped, init inherits and reaps these zombies"
+	}
+
+	if containerInit {
+		return "PID 1 in this container does not reap orphans; run it under an init such as tini (docker run --init)"
+	}
+
+	msg := fmt.Sprintf("Parent is not calling wait(); kill -CHLD %d may prompt a reap. ", parent.PID)
+	if info.Restart != "" {
+		return msg + "Restarting it (" + info.Restart + ") hands the zombies to init, which reaps them"
+	}
+	return msg + "Restarting or stopping the parent hands the zombies to init, which reaps them"
+}
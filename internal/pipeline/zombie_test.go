@@ -0,0 +1,151 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDiagnoseZombie(t *testing.T) {
+	oldest := time.Now().Add(-3 * time.Hour)
+	defer func(zc func(int) []model.Process, ws func(int) (string, string), ns func(int) string) {
+		zombieChildren, readWaitState, pidNamespace = zc, ws, ns
+	}(zombieChildren, readWaitState, pidNamespace)
+	pidNamespace = func(int) string { return "" }
+	zombieChildren = func(ppid int) []model.Process {
+		return []model.Process{{PID: 900, PPID: ppid, StartedAt: oldest}, {PID: 950, PPID: ppid}, {PID: 990, PPID: ppid}}
+	}
+
+	chain := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 812, PPID: 1, Command: "php-fpm", Service: "php-fpm"},
+		{PID: 990, PPID: 812, Command: "php", Health: "zombie"},
+	}
+
+	tests := []struct {
+		name   string
+		state  string
+		wchan  string
+		want   string
+		remedy string
+	}{
+		{"sleeping", "S", "ep_poll", "sleeping", "kill -CHLD 812 may prompt a reap"},
+		{"stopped", "T", "", "stopped", "resume it with kill -CONT 812"},
+		{"blocked", "D", "nfs_wait_on_request", "blocked (uninterruptible wait)", "blocked in the kernel (nfs_wait_on_request)"},
+	}
+	for _, tt := range tests {
+		readWaitState = func(int) (string, string) { return tt.state, tt.wchan }
+		z := diagnoseZombie(chain)
+		if z == nil {
+			t.Fatalf("%s: no diagnosis", tt.name)
+		}
+		if z.ParentPID != 812 || z.ZombieCount != 3 || z.OldestZombiePID != 900 || !z.OldestStartedAt.Equal(oldest) {
+			t.Errorf("%s: got %+v", tt.name, z)
+		}
+		if z.ParentState != tt.want || !strings.Contains(z.Remedy, tt.remedy) {
+			t.Errorf("%s: state %q remedy %q", tt.name, z.ParentState, z.Remedy)
+		}
+	}
+
+	chain[2].Health = "healthy"
+	if diagnoseZombie(chain) != nil {
+		t.Error("healthy target should not be diagnosed")
+	}
+}
+
+func TestDiagnoseZombieSkipsExitedAncestors(t *testing.T) {
+	defer func(zc func(int) []model.Process, ws func(int) (string, string), ra func(int) ([]model.Process, error), ns func(int) string) {
+		zombieChildren, readWaitState, resolveAncestry, pidNamespace = zc, ws, ra, ns
+	}(zombieChildren, readWaitState, resolveAncestry, pidNamespace)
+	pidNamespace = func(int) string { return "" }
+	zombieChildren = func(int) []model.Process { return nil }
+	readWaitState = func(int) (string, string) { return "S", "" }
+	resolveAncestry = func(pid int) ([]model.Process, error) {
+		if pid != 1500 {
+			return nil, fmt.Errorf("no process %d", pid)
+		}
+		return []model.Process{{PID: 1, Command: "systemd"}, {PID: 1500, PPID: 1, Command: "tini"}}, nil
+	}
+
+	// The target was adopted by a subreaper (1500) after its parent exited;
+	// the exited ancestor had the same PID as the subreaper
+	chain := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 700, PPID: 1, Command: "bash"},
+		{PID: 1500, PPID: 700, Command: "make", Exited: true},
+		{PID: 990, PPID: 1500, Command: "cc", Health: "zombie"},
+	}
+	z := diagnoseZombie(chain)
+	if z == nil || z.ParentPID != 1500 || z.ParentCommand != "tini" {
+		t.Fatalf("adopted zombie: got %+v, want parent tini (1500)", z)
+	}
+
+	// A live parent in the chain is used even when exited ancestors follow it
+	chain[3].PPID = 700
+	if z := diagnoseZombie(chain); z == nil || z.ParentPID != 700 || z.ParentCommand != "bash" {
+		t.Errorf("got %+v, want parent bash (700)", z)
+	}
+}
+
+func TestRestartCommand(t *testing.T) {
+	// Sources as detectSystemd and detectLaunchd build them
+	tests := []struct {
+		name string
+		src  model.Source
+		want string
+	}{
+		{"systemd service", model.Source{Type: model.SourceSystemd, Name: "systemd", UnitFile: "/usr/lib/systemd/system/php-fpm.service", Details: map[string]string{"unit": "php-fpm.service"}}, "systemctl restart php-fpm.service"},
+		{"systemd template instance", model.Source{Type: model.SourceSystemd, Name: "systemd", UnitFile: "/usr/lib/systemd/system/getty@.service", Details: map[string]string{"unit": "getty@tty1.service"}}, "systemctl restart getty@tty1.service"},
+		{"systemd session scope", model.Source{Type: model.SourceSystemd, Name: "systemd", Details: map[string]string{"unit": "session-3.scope"}}, ""},
+		{"systemd without unit", model.Source{Type: model.SourceSystemd, Name: "systemd"}, ""},
+		{"launchd label", model.Source{Type: model.SourceLaunchd, Name: "com.example.agent"}, "launchctl kickstart -k system/com.example.agent"},
+		{"launchd fallback", model.Source{Type: model.SourceLaunchd, Name: "launchd"}, ""},
+		{"shell", model.Source{Type: model.SourceShell, Name: "bash"}, ""},
+	}
+	for _, tt := range tests {
+		if got := restartCommand(tt.src); got != tt.want {
+			t.Errorf("%s: restartCommand = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiagnoseZombieContainerInit(t *testing.T) {
+	defer func(zc func(int) []model.Process, ws func(int) (string, string), ns func(int) string) {
+		zombieChildren, readWaitState, pidNamespace = zc, ws, ns
+	}(zombieChildren, readWaitState, pidNamespace)
+	zombieChildren = func(int) []model.Process { return nil }
+	readWaitState = func(int) (string, string) { return "S", "do_wait" }
+	namespaces := map[int]string{1: "pid:[4026531836]", 900: "pid:[4026531836]", 910: "pid:[4026532201]", 920: "pid:[4026532201]"}
+	pidNamespace = func(pid int) string { return namespaces[pid] }
+
+	// Host PIDs: the container's init is 910, not 1
+	chain := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 900, PPID: 1, Command: "containerd-shim"},
+		{PID: 910, PPID: 900, Command: "node", Container: "docker: web"},
+		{PID: 920, PPID: 910, Command: "sh", Container: "docker: web", Health: "zombie"},
+	}
+	if z := diagnoseZombie(chain); z == nil || !strings.Contains(z.Remedy, "does not reap orphans") {
+		t.Fatalf("container init parent: got %+v", z)
+	}
+
+	// A process below the container's init gets the generic advice
+	chain = []model.Process{
+		chain[0], chain[1], chain[2],
+		{PID: 915, PPID: 910, Command: "npm", Container: "docker: web"},
+		{PID: 920, PPID: 915, Command: "sh", Container: "docker: web", Health: "zombie"},
+	}
+	namespaces[915] = "pid:[4026532201]"
+	if z := diagnoseZombie(chain); z == nil || strings.Contains(z.Remedy, "does not reap orphans") {
+		t.Errorf("nested parent: got %+v", z)
+	}
+
+	// A host process's parent is not a container init
+	chain = []model.Process{chain[0], chain[1], {PID: 930, PPID: 900, Command: "sh", Health: "zombie"}}
+	if z := diagnoseZombie(chain); z == nil || strings.Contains(z.Remedy, "does not reap orphans") {
+		t.Errorf("host parent: got %+v", z)
+	}
+}
@@ -0,0 +1,395 @@
+//go:build linux
+
+// Package pkgdb resolves which distribution package installed a file by
+// reading the package manager databases directly.
+package pkgdb
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Package database locations. Variables so tests can point them at fixtures.
+var (
+	dpkgInfoDir  = "/var/lib/dpkg/info"
+	dpkgStatus   = "/var/lib/dpkg/status"
+	aptListsDir  = "/var/lib/apt/lists"
+	pacmanDir    = "/var/lib/pacman/local"
+	apkInstalled = "/lib/apk/db/installed"
+	rpmQuery     = func(ctx context.Context, path string) ([]byte, error) {
+		if _, err := exec.LookPath("rpm"); err != nil {
+			return nil, err
+		}
+		return exec.CommandContext(ctx, "rpm", "-qf", "--queryformat", "%{NAME}\t%{EPOCHNUM}:%{VERSION}-%{RELEASE}\t%{VENDOR}\n", path).Output()
+	}
+)
+
+// Owner returns the package owning path. When at least one package database
+// was readable but none lists the file, the result has Owned set to false.
+// Returns nil if no package database is available. The repository a dpkg
+// package came from is left to Origin, which is much slower.
+func Owner(path string) *model.PackageInfo {
+	if path == "" {
+		return nil
+	}
+	candidates := candidatePaths(path)
+
+	var consulted []string
+	lookups := []struct {
+		manager string
+		lookup  func([]string) (*model.PackageInfo, bool)
+	}{
+		{"dpkg", dpkgOwner},
+		{"pacman", pacmanOwner},
+		{"apk", apkOwner},
+		{"rpm", rpmOwner},
+	}
+	for _, l := range lookups {
+		info, available := l.lookup(candidates)
+		if info != nil {
+			info.Manager = l.manager
+			info.Owned = true
+			return info
+		}
+		if available {
+			consulted = append(consulted, l.manager)
+		}
+	}
+	if len(consulted) == 0 {
+		return nil
+	}
+	return &model.PackageInfo{Manager: strings.Join(consulted, ", ")}
+}
+
+// candidatePaths lists the spellings a package database may use for path:
+// the path itself, its symlink-resolved form and its merged-/usr alias.
+func candidatePaths(path string) []string {
+	seen := map[string]bool{}
+	var paths []string
+	add := func(p string) {
+		if p != "" && !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	add(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		add(resolved)
+	}
+	for _, p := range append([]string(nil), paths...) {
+		for _, dir := range []string{"/bin/", "/sbin/", "/lib/", "/lib64/"} {
+			if rest, ok := strings.CutPrefix(p, "/usr"+dir); ok {
+				add(dir + rest)
+			} else if rest, ok := strings.CutPrefix(p, dir); ok {
+				add("/usr" + dir + rest)
+			}
+		}
+	}
+	return paths
+}
+
+// Origin fills in the repository of an owned dpkg package from apt's
+// package lists, which can add up to hundreds of megabytes; callers only
+// ask for it when the detail is shown.
+func Origin(info *model.PackageInfo) {
+	if info == nil || !info.Owned || info.Manager != "dpkg" || info.Origin != "" {
+		return
+	}
+	info.Origin = aptOrigin(info.Name, info.Version)
+}
+
+// dpkgOwner searches the per-package file lists, then reads the version from
+// the status file.
+func dpkgOwner(paths []string) (*model.PackageInfo, bool) {
+	lists, err := filepath.Glob(filepath.Join(dpkgInfoDir, "*.list"))
+	if err != nil || len(lists) == 0 {
+		return nil, false
+	}
+	want := toSet(paths)
+	for _, list := range likelyListsFirst(lists, filepath.Base(paths[0])) {
+		if !fileHasLine(list, want) {
+			continue
+		}
+		// libc6:amd64.list -> libc6, amd64
+		name, arch, _ := strings.Cut(strings.TrimSuffix(filepath.Base(list), ".list"), ":")
+		info := &model.PackageInfo{Name: name, Architecture: arch}
+		info.Version = dpkgVersion(name, arch)
+		return info, true
+	}
+	return nil, true
+}
+
+// likelyListsFirst moves the lists of packages named like the executable
+// (nginx-core for nginx, openssh-server for sshd) to the front, so the
+// common case reads a handful of files instead of all of them.
+func likelyListsFirst(lists []string, exe string) []string {
+	exe = strings.ToLower(strings.TrimSuffix(exe, "d"))
+	likely := func(list string) bool {
+		name, _, _ := strings.Cut(strings.TrimSuffix(filepath.Base(list), ".list"), ":")
+		return len(exe) >= 2 && strings.Contains(name, exe)
+	}
+	sorted := append([]string(nil), lists...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return likely(sorted[i]) && !likely(sorted[j])
+	})
+	return sorted
+}
+
+// dpkgVersion reads the status file up to the package's stanza.
+func dpkgVersion(name, arch string) string {
+	stanza := findStanza(dpkgStatus, func(stanza map[string]string) bool {
+		if stanza["Package"] != name {
+			return false
+		}
+		return arch == "" || stanza["Architecture"] == arch || stanza["Architecture"] == "all"
+	})
+	return stanza["Version"]
+}
+
+// aptOrigin finds the repository that publishes name at version. The list
+// file name encodes the source, e.g.
+// deb.debian.org_debian_dists_bookworm_main_binary-amd64_Packages.
+func aptOrigin(name, version string) string {
+	if version == "" {
+		return ""
+	}
+	lists, _ := filepath.Glob(filepath.Join(aptListsDir, "*_Packages"))
+	for _, list := range lists {
+		if !packagesListHas(list, name, version) {
+			continue
+		}
+		base := strings.TrimSuffix(filepath.Base(list), "_Packages")
+		host, rest, _ := strings.Cut(base, "_")
+		if _, dists, ok := strings.Cut(rest, "_dists_"); ok {
+			// bookworm_main_binary-amd64 -> bookworm/main
+			parts := strings.Split(dists, "_")
+			if len(parts) >= 2 {
+				return host + " " + parts[0] + "/" + parts[1]
+			}
+		}
+		return host
+	}
+	return ""
+}
+
+func packagesListHas(path, name, version string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	inPackage := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			inPackage = false
+		case strings.HasPrefix(line, "Package: "):
+			inPackage = strings.TrimPrefix(line, "Package: ") == name
+		case inPackage && strings.HasPrefix(line, "Version: "):
+			if strings.TrimPrefix(line, "Version: ") == version {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pacmanOwner searches local/<name>-<version>/files, which list paths
+// relative to / under a %FILES% header.
+func pacmanOwner(paths []string) (*model.PackageInfo, bool) {
+	entries, err := os.ReadDir(pacmanDir)
+	if err != nil {
+		return nil, false
+	}
+	want := map[string]bool{}
+	for _, p := range paths {
+		want[strings.TrimPrefix(p, "/")] = true
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(pacmanDir, e.Name())
+		if !fileHasLine(filepath.Join(dir, "files"), want) {
+			continue
+		}
+		desc := readPacmanDesc(filepath.Join(dir, "desc"))
+		return &model.PackageInfo{Name: desc["NAME"], Version: desc["VERSION"]}, true
+	}
+	return nil, true
+}
+
+// readPacmanDesc parses the %KEY%\nvalue blocks of a pacman desc file.
+func readPacmanDesc(path string) map[string]string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	desc := map[string]string{}
+	key := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		switch {
+		case strings.HasPrefix(line, "%") && strings.HasSuffix(line, "%"):
+			key = strings.Trim(line, "%")
+		case line == "":
+			key = ""
+		case key != "" && desc[key] == "":
+			desc[key] = line
+		}
+	}
+	return desc
+}
+
+// apkOwner searches the installed database, where each package stanza lists
+// directories as F: lines followed by their files as R: lines.
+func apkOwner(paths []string) (*model.PackageInfo, bool) {
+	f, err := os.Open(apkInstalled)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	want := map[string]bool{}
+	for _, p := range paths {
+		want[strings.TrimPrefix(p, "/")] = true
+	}
+
+	var name, version, dir string
+	found := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if found {
+				break
+			}
+			name, version, dir = "", "", ""
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "P":
+			name = value
+		case "V":
+			version = value
+		case "F":
+			dir = value
+		case "R":
+			if want[dir+"/"+value] {
+				found = true
+			}
+		}
+	}
+	if !found {
+		return nil, true
+	}
+	return &model.PackageInfo{Name: name, Version: version}, true
+}
+
+// rpmOwner asks rpm, whose database is not practical to read directly.
+func rpmOwner(paths []string) (*model.PackageInfo, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	available := false
+	for _, p := range paths {
+		out, err := rpmQuery(ctx, p)
+		if err != nil {
+			// rpm exits non-zero with "file ... is not owned by any package"
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				available = true
+			}
+			continue
+		}
+		available = true
+		line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 || fields[0] == "" {
+			continue
+		}
+		info := &model.PackageInfo{Name: fields[0], Version: strings.TrimPrefix(fields[1], "0:")}
+		// rpm does not record the repository a package was installed from
+		if len(fields) > 2 && fields[2] != "(none)" {
+			info.Vendor = fields[2]
+		}
+		return info, true
+	}
+	return nil, available
+}
+
+// fileHasLine reports whether any line of path is in want.
+func fileHasLine(path string, want map[string]bool) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if want[scanner.Text()] {
+			return true
+		}
+	}
+	return false
+}
+
+// findStanza returns the first stanza of an RFC 822 style control file,
+// such as dpkg's status, that match accepts, without reading the rest.
+// Continuation lines are skipped; only single-line fields are needed.
+func findStanza(path string, match func(map[string]string) bool) map[string]string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	current := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(current) > 0 && match(current) {
+				return current
+			}
+			current = map[string]string{}
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, ":"); ok {
+			current[k] = strings.TrimSpace(v)
+		}
+	}
+	if len(current) > 0 && match(current) {
+		return current
+	}
+	return nil
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,160 @@
+//go:build linux
+
+package pkgdb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestOwnerDpkg(t *testing.T) {
+	setDatabases(t)
+	mustWrite(t, filepath.Join(dpkgInfoDir, "coreutils.list"), "/.\n/bin\n/bin/ls\n/usr/share/doc/coreutils\n")
+	mustWrite(t, filepath.Join(dpkgInfoDir, "libc6:amd64.list"), "/.\n/usr/lib/x86_64-linux-gnu/libc.so.6\n")
+	mustWrite(t, dpkgStatus, `Package: libc6
+Status: install ok installed
+Architecture: amd64
+Version: 2.36-9+deb12u4
+
+Package: coreutils
+Status: install ok installed
+Architecture: amd64
+Version: 9.1-1
+Description: GNU core utilities
+ This package contains the basic file, shell and text manipulation
+ utilities which are expected to exist on every operating system.
+`)
+	mustWrite(t, filepath.Join(aptListsDir, "deb.debian.org_debian_dists_bookworm_main_binary-amd64_Packages"), `Package: coreutils
+Version: 9.1-1
+Architecture: amd64
+
+Package: curl
+Version: 7.88.1-10
+`)
+
+	// merged /usr: the process runs /usr/bin/ls, dpkg lists /bin/ls
+	info := Owner("/usr/bin/ls")
+	if info == nil || !info.Owned {
+		t.Fatalf("Owner(/usr/bin/ls) = %+v, want owned", info)
+	}
+	if info.Manager != "dpkg" || info.Name != "coreutils" || info.Version != "9.1-1" || info.Origin != "" {
+		t.Errorf("got %+v", info)
+	}
+	// The apt lists are only read on request
+	Origin(info)
+	if info.Origin != "deb.debian.org bookworm/main" {
+		t.Errorf("Origin = %q", info.Origin)
+	}
+
+	info = Owner("/usr/lib/x86_64-linux-gnu/libc.so.6")
+	Origin(info)
+	if info == nil || info.Name != "libc6" || info.Version != "2.36-9+deb12u4" || info.Origin != "" {
+		t.Errorf("Owner(libc.so.6) = %+v", info)
+	}
+
+	info = Owner("/usr/local/bin/node")
+	if info == nil || info.Owned || info.Manager != "dpkg" {
+		t.Errorf("Owner(/usr/local/bin/node) = %+v, want not owned (dpkg)", info)
+	}
+}
+
+func TestOwnerPacman(t *testing.T) {
+	setDatabases(t)
+	dir := filepath.Join(pacmanDir, "openssh-9.6p1-1")
+	mustWrite(t, filepath.Join(dir, "files"), "%FILES%\netc/\netc/ssh/sshd_config\nusr/bin/sshd\n\n%BACKUP%\netc/ssh/sshd_config\tabc\n")
+	mustWrite(t, filepath.Join(dir, "desc"), "%NAME%\nopenssh\n\n%VERSION%\n9.6p1-1\n\n%DESC%\nSSH protocol implementation\n")
+
+	info := Owner("/usr/bin/sshd")
+	if info == nil || !info.Owned || info.Manager != "pacman" || info.Name != "openssh" || info.Version != "9.6p1-1" {
+		t.Errorf("Owner(/usr/bin/sshd) = %+v", info)
+	}
+}
+
+func TestOwnerApk(t *testing.T) {
+	setDatabases(t)
+	mustWrite(t, apkInstalled, `C:Q1abc=
+P:musl
+V:1.2.4-r2
+F:lib
+R:ld-musl-x86_64.so.1
+
+C:Q1def=
+P:busybox
+V:1.36.1-r15
+o:busybox
+F:bin
+R:busybox
+F:etc
+R:securetty
+`)
+
+	info := Owner("/bin/busybox")
+	if info == nil || !info.Owned || info.Manager != "apk" || info.Name != "busybox" || info.Version != "1.36.1-r15" {
+		t.Errorf("Owner(/bin/busybox) = %+v", info)
+	}
+}
+
+func TestOwnerRpmFallback(t *testing.T) {
+	setDatabases(t)
+	rpmQuery = func(_ context.Context, path string) ([]byte, error) {
+		if path == "/usr/sbin/nginx" {
+			return []byte("nginx\t1:1.24.0-1.fc39\tFedora Project\n"), nil
+		}
+		return []byte("file " + path + " is not owned by any package\n"), &exec.ExitError{}
+	}
+
+	info := Owner("/usr/sbin/nginx")
+	if info == nil || !info.Owned || info.Manager != "rpm" || info.Name != "nginx" || info.Version != "1:1.24.0-1.fc39" || info.Vendor != "Fedora Project" || info.Origin != "" {
+		t.Errorf("Owner(/usr/sbin/nginx) = %+v", info)
+	}
+	if info := Owner("/opt/app/server"); info == nil || info.Owned || info.Manager != "rpm" {
+		t.Errorf("Owner(/opt/app/server) = %+v, want not owned (rpm)", info)
+	}
+}
+
+func TestLikelyListsFirst(t *testing.T) {
+	lists := []string{"/i/adduser.list", "/i/libc6:amd64.list", "/i/openssh-server.list", "/i/nginx-core.list"}
+	if got := likelyListsFirst(lists, "sshd"); got[0] != "/i/openssh-server.list" {
+		t.Errorf("sshd: %v", got)
+	}
+	if got := likelyListsFirst(lists, "nginx"); got[0] != "/i/nginx-core.list" || len(got) != len(lists) {
+		t.Errorf("nginx: %v", got)
+	}
+}
+
+func TestOwnerWithoutDatabases(t *testing.T) {
+	setDatabases(t)
+	if info := Owner("/usr/bin/ls"); info != nil {
+		t.Errorf("Owner() = %+v, want nil", info)
+	}
+}
+
+// setDatabases points every database at an empty temp dir and disables rpm.
+func setDatabases(t *testing.T) {
+	t.Helper()
+	root := t.TempDir()
+	origInfo, origStatus, origLists, origPacman, origApk, origRpm := dpkgInfoDir, dpkgStatus, aptListsDir, pacmanDir, apkInstalled, rpmQuery
+	dpkgInfoDir = filepath.Join(root, "var/lib/dpkg/info")
+	dpkgStatus = filepath.Join(root, "var/lib/dpkg/status")
+	aptListsDir = filepath.Join(root, "var/lib/apt/lists")
+	pacmanDir = filepath.Join(root, "var/lib/pacman/local")
+	apkInstalled = filepath.Join(root, "lib/apk/db/installed")
+	rpmQuery = func(context.Context, string) ([]byte, error) { return nil, errors.New("rpm not installed") }
+	t.Cleanup(func() {
+		dpkgInfoDir, dpkgStatus, aptListsDir, pacmanDir, apkInstalled, rpmQuery = origInfo, origStatus, origLists, origPacman, origApk, origRpm
+	})
+}
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
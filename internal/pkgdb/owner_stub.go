@@ -0,0 +1,16 @@
+//go:build !linux
+
+package pkgdb
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// Owner returns nil: package ownership is only resolved on Linux.
+func Owner(path string) *model.PackageInfo {
+	return nil
+}
+
+// Origin is a no-op: package ownership is only resolved on Linux.
+func Origin(info *model.PackageInfo) {}
+
+// Verify is a no-op: package integrity is only checked on Linux.
+func Verify(info *model.PackageInfo, path string) {}
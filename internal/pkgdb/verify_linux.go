@@ -0,0 +1,138 @@
+//go:build linux
+
+package pkgdb
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// rpmVerify runs rpm's verification of the package owning path. A variable
+// so tests can fake its output.
+var rpmVerify = func(ctx context.Context, path string) ([]byte, error) {
+	if _, err := exec.LookPath("rpm"); err != nil {
+		return nil, err
+	}
+	return exec.CommandContext(ctx, "rpm", "-Vf", path).Output()
+}
+
+// Verify compares path against the digest its package manager recorded at
+// install time and stores the outcome in info.Integrity.
+func Verify(info *model.PackageInfo, path string) {
+	if info == nil || !info.Owned || path == "" {
+		return
+	}
+	switch info.Manager {
+	case "dpkg":
+		info.Integrity = dpkgVerify(info, path)
+	case "rpm":
+		info.Integrity = rpmVerifyFile(path)
+	default:
+		info.Integrity = model.IntegrityUnverified
+	}
+}
+
+// dpkgVerify checks the file against <package>[:arch].md5sums, which lists
+// "<md5>  <path relative to />" for every shipped file except conffiles.
+func dpkgVerify(info *model.PackageInfo, path string) string {
+	sums := filepath.Join(dpkgInfoDir, info.Name+".md5sums")
+	if info.Architecture != "" {
+		if multiarch := filepath.Join(dpkgInfoDir, info.Name+":"+info.Architecture+".md5sums"); fileExists(multiarch) {
+			sums = multiarch
+		}
+	}
+	f, err := os.Open(sums)
+	if err != nil {
+		return model.IntegrityUnverified
+	}
+	defer f.Close()
+
+	want := map[string]bool{}
+	for _, p := range candidatePaths(path) {
+		want[strings.TrimPrefix(p, "/")] = true
+	}
+	recorded := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		sum, file, ok := strings.Cut(scanner.Text(), "  ")
+		if ok && want[file] {
+			recorded = sum
+			break
+		}
+	}
+	if recorded == "" {
+		return model.IntegrityUnverified
+	}
+
+	actual, err := md5File(path)
+	if err != nil {
+		return model.IntegrityUnverified
+	}
+	if !strings.EqualFold(actual, recorded) {
+		return model.IntegrityModified
+	}
+	return model.IntegrityVerified
+}
+
+// rpmVerifyFile parses `rpm -Vf` output, which lists only files that fail
+// a check: "S.5....T.  c /etc/foo", "missing     /usr/bin/foo".
+func rpmVerifyFile(path string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	out, err := rpmVerify(ctx, path)
+	if err != nil {
+		// rpm exits non-zero when any file of the package fails verification
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return model.IntegrityUnverified
+		}
+	}
+	want := toSet(candidatePaths(path))
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !want[fields[len(fields)-1]] {
+			continue
+		}
+		flags := fields[0]
+		switch {
+		case flags == "missing":
+			return model.IntegrityModified
+		case len(flags) >= 3 && flags[2] == '?':
+			// digest could not be computed (usually permissions)
+			return model.IntegrityUnverified
+		case len(flags) >= 3 && (flags[0] == 'S' || flags[2] == '5'):
+			return model.IntegrityModified
+		}
+	}
+	return model.IntegrityVerified
+}
+
+func md5File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
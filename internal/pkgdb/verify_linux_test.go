@@ -0,0 +1,77 @@
+//go:build linux
+
+package pkgdb
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestVerifyDpkg(t *testing.T) {
+	setDatabases(t)
+	bin := filepath.Join(t.TempDir(), "usr/bin/tool")
+	mustWrite(t, bin, "hello\n")
+	rel := strings.TrimPrefix(bin, "/")
+
+	tests := []struct {
+		name   string
+		sums   string
+		arch   string
+		file   string
+		expect string
+	}{
+		// md5("hello\n")
+		{"matches", "b1946ac92492d2347c6235b4d2611184  " + rel + "\n", "", "tool.md5sums", model.IntegrityVerified},
+		{"multiarch", "b1946ac92492d2347c6235b4d2611184  " + rel + "\n", "amd64", "tool:amd64.md5sums", model.IntegrityVerified},
+		{"differs", "0123456789abcdef0123456789abcdef  " + rel + "\n", "", "tool.md5sums", model.IntegrityModified},
+		{"not recorded", "b1946ac92492d2347c6235b4d2611184  usr/share/doc/tool/README\n", "", "tool.md5sums", model.IntegrityUnverified},
+		{"no md5sums", "", "", "", model.IntegrityUnverified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setDatabases(t)
+			if tt.file != "" {
+				mustWrite(t, filepath.Join(dpkgInfoDir, tt.file), tt.sums)
+			}
+			info := &model.PackageInfo{Manager: "dpkg", Owned: true, Name: "tool", Architecture: tt.arch}
+			Verify(info, bin)
+			if info.Integrity != tt.expect {
+				t.Fatalf("Integrity = %q, want %q", info.Integrity, tt.expect)
+			}
+		})
+	}
+}
+
+func TestVerifyRpm(t *testing.T) {
+	tests := []struct {
+		name   string
+		out    string
+		err    error
+		expect string
+	}{
+		{"clean", "", nil, model.IntegrityVerified},
+		{"other file changed", "S.5....T.  c /etc/nginx/nginx.conf\n", &exec.ExitError{}, model.IntegrityVerified},
+		{"digest mismatch", "..5....T.    /usr/sbin/nginx\n", &exec.ExitError{}, model.IntegrityModified},
+		{"missing", "missing     /usr/sbin/nginx\n", &exec.ExitError{}, model.IntegrityModified},
+		{"unreadable", "..?......    /usr/sbin/nginx\n", &exec.ExitError{}, model.IntegrityUnverified},
+		{"no rpm", "", exec.ErrNotFound, model.IntegrityUnverified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := rpmVerify
+			rpmVerify = func(context.Context, string) ([]byte, error) { return []byte(tt.out), tt.err }
+			t.Cleanup(func() { rpmVerify = orig })
+
+			info := &model.PackageInfo{Manager: "rpm", Owned: true, Name: "nginx"}
+			Verify(info, "/usr/sbin/nginx")
+			if info.Integrity != tt.expect {
+				t.Fatalf("Integrity = %q, want %q", info.Integrity, tt.expect)
+			}
+		})
+	}
+}
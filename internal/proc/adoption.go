@@ -0,0 +1,88 @@
+package proc
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// subreaperPrefixes name processes that adopt orphaned descendants, either
+// as PID 1 of a namespace or via PR_SET_CHILD_SUBREAPER. comm is truncated
+// to 15 bytes, so long names are matched by prefix.
+var subreaperPrefixes = []string{
+	"systemd", "init", "tini", "docker-init", "dumb-init", "catatonit",
+	"containerd-shim", "conmon", "s6-svscan", "runsvdir", "launchd",
+}
+
+func isSubreaper(comm string) bool {
+	for _, prefix := range subreaperPrefixes {
+		if strings.HasPrefix(comm, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// processExists is overridable for tests
+var processExists = pidExists
+
+// classifyAdoption decides whether p was reparented to parent after its
+// original parent exited. Only init and known subreapers adopt, so any
+// other parent is the real one. It returns the refined Forked value and,
+// for adopted processes, the evidence.
+func classifyAdoption(p, parent model.Process) (string, *model.AdoptionInfo) {
+	if p.KernelThread || p.PID == 1 || parent.PID == 0 {
+		return p.Forked, nil
+	}
+	subreaper := parent.PID != 1
+	if subreaper && !isSubreaper(parent.Command) {
+		return "forked", nil
+	}
+
+	var evidence []string
+	if !p.StartedAt.IsZero() && parent.StartedAt.After(p.StartedAt) {
+		evidence = append(evidence, parent.Command+" (pid "+strconv.Itoa(parent.PID)+") started after this process")
+	}
+	if leaderGone(p.PGID, p, parent) {
+		evidence = append(evidence, "process group leader "+strconv.Itoa(p.PGID)+" has exited")
+	}
+	if leaderGone(p.SessionID, p, parent) {
+		evidence = append(evidence, "session leader "+strconv.Itoa(p.SessionID)+" has exited")
+	}
+	if len(evidence) == 0 {
+		// Started directly by its service manager or init
+		return "not-forked", nil
+	}
+
+	// Detached on purpose: nohup, setsid, or the classic double fork where
+	// the first child leads a new session and exits
+	forked := "adopted"
+	switch {
+	case p.IgnoresHangup:
+		evidence = append(evidence, "ignores SIGHUP (started via nohup)")
+		forked = "daemonized"
+	case p.SessionID == p.PID:
+		evidence = append(evidence, "leads its own session (setsid)")
+		forked = "daemonized"
+	case p.SessionID > 0 && p.SessionID == p.PGID && !p.ControllingTTY && leaderGone(p.SessionID, p, parent):
+		evidence = append(evidence, "double fork: session and group leader exited, no controlling terminal")
+		forked = "daemonized"
+	}
+
+	return forked, &model.AdoptionInfo{
+		AdopterPID:     parent.PID,
+		AdopterCommand: parent.Command,
+		Subreaper:      subreaper,
+		Evidence:       evidence,
+	}
+}
+
+// leaderGone reports whether a group or session led by some other process
+// has lost its leader. The parent leading it is the normal case.
+func leaderGone(leader int, p, parent model.Process) bool {
+	if leader <= 0 || leader == p.PID || leader == parent.PID {
+		return false
+	}
+	return !processExists(leader)
+}
@@ -0,0 +1,13 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"strconv"
+)
+
+func pidExists(pid int) bool {
+	_, err := os.Stat("/proc/" + strconv.Itoa(pid))
+	return err == nil
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package proc
+
+// pidExists assumes the process is alive. Group and session IDs are only
+// collected on Linux, so this is not reached elsewhere.
+func pidExists(pid int) bool { return true }
@@ -0,0 +1,50 @@
+package proc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestClassifyAdoption(t *testing.T) {
+	defer func(f func(int) bool) { processExists = f }(processExists)
+	alive := map[int]bool{1: true, 700: true}
+	processExists = func(pid int) bool { return alive[pid] }
+
+	boot := time.Now().Add(-time.Hour)
+	initProc := model.Process{PID: 1, Command: "systemd", StartedAt: boot}
+	shim := model.Process{PID: 650, Command: "containerd-shim", StartedAt: boot.Add(30 * time.Minute)}
+	shell := model.Process{PID: 700, Command: "bash", StartedAt: boot.Add(time.Minute)}
+	started := boot.Add(10 * time.Minute)
+
+	tests := []struct {
+		name      string
+		proc      model.Process
+		parent    model.Process
+		want      string
+		adopted   bool
+		subreaper bool
+	}{
+		{"child of a live shell", model.Process{PID: 800, PPID: 700, PGID: 800, SessionID: 700, StartedAt: started}, shell, "forked", false, false},
+		{"service started by init", model.Process{PID: 800, PPID: 1, PGID: 800, SessionID: 800, StartedAt: started, Forked: "not-forked"}, initProc, "not-forked", false, false},
+		{"background job whose shell exited", model.Process{PID: 800, PPID: 1, PGID: 790, SessionID: 780, ControllingTTY: true, StartedAt: started}, initProc, "adopted", true, false},
+		{"nohup", model.Process{PID: 800, PPID: 1, PGID: 790, SessionID: 780, IgnoresHangup: true, StartedAt: started}, initProc, "daemonized", true, false},
+		{"double fork", model.Process{PID: 800, PPID: 1, PGID: 790, SessionID: 790, StartedAt: started}, initProc, "daemonized", true, false},
+		{"reparented to a younger subreaper", model.Process{PID: 800, PPID: 650, PGID: 800, SessionID: 700, StartedAt: started}, shim, "adopted", true, true},
+		{"kernel thread", model.Process{PID: 40, PPID: 2, KernelThread: true, Forked: "not-forked"}, model.Process{PID: 2, Command: "kthreadd"}, "not-forked", false, false},
+	}
+	for _, tt := range tests {
+		got, info := classifyAdoption(tt.proc, tt.parent)
+		if got != tt.want {
+			t.Errorf("%s: Forked = %q, want %q", tt.name, got, tt.want)
+		}
+		if (info != nil) != tt.adopted {
+			t.Errorf("%s: adoption = %+v, want adopted %v", tt.name, info, tt.adopted)
+			continue
+		}
+		if info != nil && (info.AdopterPID != tt.parent.PID || info.Subreaper != tt.subreaper || len(info.Evidence) == 0) {
+			t.Errorf("%s: adoption = %+v", tt.name, info)
+		}
+	}
+}
@@ -23,6 +23,7 @@ func ResolveAncestry(pid int) ([]model.Process, error) {
 			break
 		}
 
+		p.Entrypoint = extractEntrypoint(p.Command, p.Cmdline, p.WorkingDir)
 		chain = append(chain, p)
 
 		if p.PPID == 0 || p.PID == 1 {
@@ -40,5 +41,10 @@ func ResolveAncestry(pid int) ([]model.Process, error) {
 		chain[i], chain[j] = chain[j], chain[i]
 	}
 
+	// Now each process sits after its current parent
+	for i := 1; i < len(chain); i++ {
+		chain[i].Forked, chain[i].Adoption = classifyAdoption(chain[i], chain[i-1])
+	}
+
 	return chain, nil
 }
@@ -0,0 +1,53 @@
+//go:build linux
+
+package proc
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ReadConnections returns the TCP and UDP sockets held by pid, with their
+// remote ends and queue sizes. Tables are read from the process's own
+// network namespace so containerised processes are covered too.
+func ReadConnections(pid int) []model.Socket {
+	inodes := socketsForPID(pid)
+	if len(inodes) == 0 {
+		return nil
+	}
+	sockets := readSocketTables("/proc/" + strconv.Itoa(pid) + "/net")
+	if len(sockets) == 0 {
+		sockets, _ = readSockets()
+	}
+
+	var conns []model.Socket
+	for _, inode := range inodes {
+		if sock, ok := sockets[inode]; ok {
+			conns = append(conns, sock)
+		}
+	}
+	sortConnections(conns)
+	return conns
+}
+
+// sortConnections orders listeners first, then by local port and remote end.
+func sortConnections(conns []model.Socket) {
+	sort.SliceStable(conns, func(i, j int) bool {
+		a, b := conns[i], conns[j]
+		if (a.State == "LISTEN") != (b.State == "LISTEN") {
+			return a.State == "LISTEN"
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		if a.RemoteAddress != b.RemoteAddress {
+			return a.RemoteAddress < b.RemoteAddress
+		}
+		return a.RemotePort < b.RemotePort
+	})
+}
+
+// ConnectionsSupported reports whether ReadConnections works on this platform.
+const ConnectionsSupported = true
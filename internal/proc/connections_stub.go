@@ -0,0 +1,13 @@
+//go:build !linux
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// ReadConnections is only implemented on Linux.
+func ReadConnections(pid int) []model.Socket {
+	return nil
+}
+
+// ConnectionsSupported reports whether ReadConnections works on this platform.
+const ConnectionsSupported = false
@@ -1,56 +1,24 @@
 package proc
 
 import (
-	"context"
-	"fmt"
 	"os/exec"
 	"strings"
-	"time"
 	"unicode"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-// ResolveContainerByPort queries the Docker CLI for a container publishing the given port.
-// Returns nil if Docker is not available or no container matches.
+// ResolveContainerByPort finds the Docker container publishing the given port.
+// The Engine API is queried first, then the daemon's on-disk state; the Docker
+// CLI is only used as a fallback. Returns nil if no container matches.
 func ResolveContainerByPort(port int) *model.DockerPortMatch {
-	if _, err := exec.LookPath("docker"); err != nil {
-		return nil
+	if info := engineContainerByPort(port); info != nil {
+		return dockerPortMatch(info)
 	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
-	format := "{{.ID}}|{{.Names}}|{{.Image}}|{{.Ports}}|{{.Label \"com.docker.compose.project\"}}|{{.Label \"com.docker.compose.service\"}}"
-	cmd := exec.CommandContext(ctx, "docker", "ps", "--filter", fmt.Sprintf("publish=%d", port), "--format", format)
-	out, err := cmd.Output()
-	if err != nil {
-		return nil
-	}
-
-	line := strings.TrimSpace(string(out))
-	if line == "" {
-		return nil
-	}
-
-	// Take the first matching container if multiple lines
-	if idx := strings.Index(line, "\n"); idx >= 0 {
-		line = line[:idx]
-	}
-
-	parts := strings.SplitN(line, "|", 6)
-	if len(parts) < 6 {
-		return nil
-	}
-
-	return &model.DockerPortMatch{
-		ID:             parts[0],
-		Name:           parts[1],
-		Image:          parts[2],
-		Ports:          parts[3],
-		ComposeProject: parts[4],
-		ComposeService: parts[5],
+	if info := dockerContainerByPort(port); info != nil {
+		return dockerPortMatch(info)
 	}
+	return dockerPSByPort(port)
 }
 
 // resolveContainerName attempts to resolve a container ID to a name using the specified runtime CLI.
@@ -60,12 +28,11 @@ func resolveContainerName(id, runtime string) string {
 
 	switch runtime {
 	case "docker":
-		if _, err := exec.LookPath("docker"); err != nil {
-			return ""
-		}
-		cmd = exec.Command("docker", "inspect", id, "--format", "{{.Name}}|{{index .Config.Labels \"com.docker.compose.project\"}}|{{index .Config.Labels \"com.docker.compose.service\"}}")
-		prefix = "docker: "
+		return dockerContainerLabel(resolveDockerContainer(id))
 	case "podman":
+		if info := inspectEngine(podmanSockets(), "podman", id); info != nil && info.Name != "" {
+			return "podman: " + info.Name
+		}
 		if _, err := exec.LookPath("podman"); err != nil {
 			return ""
 		}
@@ -93,23 +60,6 @@ func resolveContainerName(id, runtime string) string {
 	}
 	output := strings.TrimSpace(string(out))
 
-	if runtime == "docker" {
-		parts := strings.Split(output, "|")
-		if len(parts) == 3 {
-			name := strings.TrimPrefix(parts[0], "/")
-			project := parts[1]
-			service := parts[2]
-
-			if project != "" && service != "" {
-				return "docker: " + project + "/" + service + " (" + name + ")"
-			}
-			if name != "" {
-				return "docker: " + name
-			}
-			return ""
-		}
-	}
-
 	name := strings.TrimPrefix(output, "/")
 	if name != "" {
 		if prefix != "" {
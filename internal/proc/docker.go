@@ -0,0 +1,403 @@
+package proc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// dockerRoot is the daemon's data root. A variable so tests can use fixtures.
+var dockerRoot = "/var/lib/docker"
+
+// dockerCLI runs the docker CLI. A variable so tests can fake its output.
+var dockerCLI = func(ctx context.Context, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, err
+	}
+	return exec.CommandContext(ctx, "docker", args...).Output()
+}
+
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+	composeFilesLabel   = "com.docker.compose.project.config_files"
+)
+
+type dockerPortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string
+}
+
+type dockerConfig struct {
+	Image       string
+	Env         []string
+	Labels      map[string]string
+	Healthcheck *struct {
+		Test []string
+	}
+}
+
+type dockerState struct {
+	Running   bool
+	StartedAt string
+	Health    *struct {
+		Status string
+	}
+}
+
+type dockerNetworkSettings struct {
+	Ports    map[string][]dockerPortBinding
+	Networks map[string]struct {
+		IPAddress string
+	}
+}
+
+type dockerHostConfig struct {
+	RestartPolicy struct {
+		Name string
+	}
+	PortBindings map[string][]dockerPortBinding
+}
+
+type dockerMount struct {
+	Type        string
+	Name        string
+	Source      string
+	Destination string
+	RW          bool
+}
+
+// dockerContainer is the subset of container state witr reads. It matches
+// both config.v2.json (plus hostconfig.json) and `docker inspect` output.
+type dockerContainer struct {
+	ID              string
+	Name            string
+	RestartCount    int
+	Config          dockerConfig
+	State           dockerState
+	NetworkSettings dockerNetworkSettings
+	HostConfig      *dockerHostConfig
+	MountPoints     map[string]dockerMount // on-disk layout
+	Mounts          []dockerMount          // docker inspect layout
+}
+
+// readDockerState loads a container's config.v2.json and hostconfig.json.
+// id may be a full ID or a unique prefix.
+func readDockerState(id string) *dockerContainer {
+	if id == "" {
+		return nil
+	}
+	dir := filepath.Join(dockerRoot, "containers", id)
+	if _, err := os.Stat(dir); err != nil {
+		matches, _ := filepath.Glob(filepath.Join(dockerRoot, "containers", id+"*"))
+		if len(matches) != 1 {
+			return nil
+		}
+		dir = matches[0]
+	}
+	return readDockerStateDir(dir)
+}
+
+func readDockerStateDir(dir string) *dockerContainer {
+	data, err := os.ReadFile(filepath.Join(dir, "config.v2.json"))
+	if err != nil {
+		return nil
+	}
+	var c dockerContainer
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "hostconfig.json")); err == nil {
+		var hc dockerHostConfig
+		if err := json.Unmarshal(data, &hc); err == nil {
+			c.HostConfig = &hc
+		}
+	}
+	return &c
+}
+
+// listDockerStates reads the on-disk state of every container.
+func listDockerStates() []*dockerContainer {
+	entries, err := os.ReadDir(filepath.Join(dockerRoot, "containers"))
+	if err != nil {
+		return nil
+	}
+	var containers []*dockerContainer
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if c := readDockerStateDir(filepath.Join(dockerRoot, "containers", e.Name())); c != nil {
+			containers = append(containers, c)
+		}
+	}
+	return containers
+}
+
+// inspectDockerCLI runs `docker inspect` for a container.
+func inspectDockerCLI(id string) *dockerContainer {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	out, err := dockerCLI(ctx, "inspect", "--type", "container", id)
+	if err != nil {
+		return nil
+	}
+	var containers []dockerContainer
+	if err := json.Unmarshal(out, &containers); err != nil || len(containers) == 0 {
+		return nil
+	}
+	return &containers[0]
+}
+
+// resolveDockerContainer returns container metadata from the Engine API,
+// the on-disk state, or the docker CLI, whichever answers first.
+func resolveDockerContainer(id string) *model.ContainerInfo {
+	if info := inspectEngine(dockerSockets(), "docker", id); info != nil {
+		return info
+	}
+	if c := readDockerState(id); c != nil {
+		return c.info()
+	}
+	if c := inspectDockerCLI(id); c != nil {
+		return c.info()
+	}
+	return nil
+}
+
+func (c *dockerContainer) info() *model.ContainerInfo {
+	info := &model.ContainerInfo{
+		Runtime:        "docker",
+		ID:             c.ID,
+		Name:           strings.TrimPrefix(c.Name, "/"),
+		Image:          c.Config.Image,
+		ComposeProject: c.Config.Labels[composeProjectLabel],
+		ComposeService: c.Config.Labels[composeServiceLabel],
+		ComposeFiles:   c.Config.Labels[composeFilesLabel],
+		Ports:          c.ports(),
+		Mounts:         c.mounts(),
+		HealthCheck:    c.healthCheck(),
+		RestartCount:   c.RestartCount,
+		Env:            c.Config.Env,
+	}
+	if t, err := time.Parse(time.RFC3339Nano, c.State.StartedAt); err == nil && !t.IsZero() && t.Year() > 1 {
+		info.StartedAt = t
+	}
+	if c.HostConfig != nil {
+		info.RestartPolicy = c.HostConfig.RestartPolicy.Name
+	}
+	if c.State.Health != nil {
+		info.HealthStatus = c.State.Health.Status
+	}
+	return info
+}
+
+// ports formats published ports the way `docker ps` does ("0.0.0.0:8080->80/tcp").
+// Live bindings are preferred; requested bindings are used for stopped containers.
+func (c *dockerContainer) ports() []string {
+	bindings := c.NetworkSettings.Ports
+	if len(bindings) == 0 && c.HostConfig != nil {
+		bindings = c.HostConfig.PortBindings
+	}
+	var ports []string
+	for containerPort, hostBindings := range bindings {
+		for _, b := range hostBindings {
+			if b.HostPort == "" {
+				continue
+			}
+			hostIP := b.HostIP
+			if hostIP == "" {
+				hostIP = "0.0.0.0"
+			}
+			ports = append(ports, hostIP+":"+b.HostPort+"->"+containerPort)
+		}
+	}
+	sort.Strings(ports)
+	return ports
+}
+
+// publishes reports whether the container binds the given host port.
+func (c *dockerContainer) publishes(port int) bool {
+	want := strconv.Itoa(port)
+	check := func(bindings map[string][]dockerPortBinding) bool {
+		for _, hostBindings := range bindings {
+			for _, b := range hostBindings {
+				if b.HostPort == want {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if check(c.NetworkSettings.Ports) {
+		return true
+	}
+	return c.HostConfig != nil && check(c.HostConfig.PortBindings)
+}
+
+func (c *dockerContainer) mounts() []string {
+	all := c.Mounts
+	for _, m := range c.MountPoints {
+		all = append(all, m)
+	}
+	var mounts []string
+	for _, m := range all {
+		src := m.Source
+		if m.Type == "volume" && m.Name != "" {
+			src = m.Name
+		}
+		entry := src + ":" + m.Destination
+		if !m.RW {
+			entry += " (ro)"
+		}
+		mounts = append(mounts, entry)
+	}
+	sort.Strings(mounts)
+	return mounts
+}
+
+// healthCheck returns the configured health check command, or "" if none.
+func (c *dockerContainer) healthCheck() string {
+	if c.Config.Healthcheck == nil || len(c.Config.Healthcheck.Test) == 0 {
+		return ""
+	}
+	test := c.Config.Healthcheck.Test
+	switch test[0] {
+	case "NONE":
+		return ""
+	case "CMD", "CMD-SHELL":
+		return strings.Join(test[1:], " ")
+	}
+	return strings.Join(test, " ")
+}
+
+// dockerContainerLabel formats container metadata for the Container field.
+func dockerContainerLabel(info *model.ContainerInfo) string {
+	if info == nil {
+		return ""
+	}
+	if info.ComposeProject != "" && info.ComposeService != "" {
+		return "docker: " + info.ComposeProject + "/" + info.ComposeService + " (" + info.Name + ")"
+	}
+	if info.Name != "" {
+		return "docker: " + info.Name
+	}
+	return ""
+}
+
+// dockerPortMatch converts container metadata into a port lookup result.
+func dockerPortMatch(info *model.ContainerInfo) *model.DockerPortMatch {
+	id := info.ID
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return &model.DockerPortMatch{
+		ID:             id,
+		Name:           info.Name,
+		Image:          info.Image,
+		Ports:          strings.Join(info.Ports, ", "),
+		ComposeProject: info.ComposeProject,
+		ComposeService: info.ComposeService,
+		RestartPolicy:  info.RestartPolicy,
+		Health:         info.HealthStatus,
+		RestartCount:   info.RestartCount,
+	}
+}
+
+// dockerContainerByPort finds a running container publishing port in the on-disk state.
+func dockerContainerByPort(port int) *model.ContainerInfo {
+	for _, c := range listDockerStates() {
+		if c.State.Running && c.publishes(port) {
+			return c.info()
+		}
+	}
+	return nil
+}
+
+// dockerContainerByIP finds a running container attached to a network with the given IP.
+func dockerContainerByIP(ip string) *model.ContainerInfo {
+	for _, c := range listDockerStates() {
+		if !c.State.Running {
+			continue
+		}
+		for _, n := range c.NetworkSettings.Networks {
+			if n.IPAddress == ip {
+				return c.info()
+			}
+		}
+	}
+	return nil
+}
+
+// dockerPSByPort asks the docker CLI for a container publishing port.
+func dockerPSByPort(port int) *model.DockerPortMatch {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	format := "{{.ID}}|{{.Names}}|{{.Image}}|{{.Ports}}|{{.Label \"com.docker.compose.project\"}}|{{.Label \"com.docker.compose.service\"}}"
+	out, err := dockerCLI(ctx, "ps", "--filter", fmt.Sprintf("publish=%d", port), "--format", format)
+	if err != nil {
+		return nil
+	}
+
+	line := strings.TrimSpace(string(out))
+	if line == "" {
+		return nil
+	}
+
+	// Take the first matching container if multiple lines
+	if idx := strings.Index(line, "\n"); idx >= 0 {
+		line = line[:idx]
+	}
+
+	parts := strings.SplitN(line, "|", 6)
+	if len(parts) < 6 {
+		return nil
+	}
+
+	return &model.DockerPortMatch{
+		ID:             parts[0],
+		Name:           parts[1],
+		Image:          parts[2],
+		Ports:          parts[3],
+		ComposeProject: parts[4],
+		ComposeService: parts[5],
+	}
+}
+
+// dockerNetworkContainerByIP asks the docker CLI which bridge container owns ip.
+func dockerNetworkContainerByIP(ip string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	out, err := dockerCLI(ctx, "network", "inspect", "bridge",
+		"--format", "{{range .Containers}}{{.Name}}:{{.IPv4Address}}{{\"\\n\"}}{{end}}")
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		colonIdx := strings.Index(line, ":")
+		if colonIdx == -1 {
+			continue
+		}
+		name := line[:colonIdx]
+		addr := strings.Split(line[colonIdx+1:], "/")[0]
+		if addr == ip {
+			return name
+		}
+	}
+	return ""
+}
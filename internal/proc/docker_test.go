@@ -0,0 +1,144 @@
+package proc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDockerID = "4f9c1e2d3b4a5f6e7d8c9b0a1f2e3d4c5b6a7f8e9d0c1b2a3f4e5d6c7b8a9f0e"
+
+func TestResolveDockerContainerFromDisk(t *testing.T) {
+	setDockerRoot(t, "testdata/docker")
+	stubDockerCLI(t, func(args []string) ([]byte, error) {
+		t.Fatalf("docker CLI called with %v; on-disk state should be enough", args)
+		return nil, nil
+	})
+
+	info := resolveDockerContainer(testDockerID[:12])
+	if info == nil {
+		t.Fatal("resolveDockerContainer() = nil")
+	}
+	if got := dockerContainerLabel(info); got != "docker: shop/web (shop-web-1)" {
+		t.Errorf("dockerContainerLabel() = %q", got)
+	}
+	if info.Image != "nginx:1.27" || info.RestartPolicy != "unless-stopped" {
+		t.Errorf("Image, RestartPolicy = %q, %q", info.Image, info.RestartPolicy)
+	}
+	if info.ComposeFiles != "/srv/shop/compose.yaml" {
+		t.Errorf("ComposeFiles = %q", info.ComposeFiles)
+	}
+	wantPorts := []string{"0.0.0.0:8080->80/tcp", ":::8080->80/tcp"}
+	if !reflect.DeepEqual(info.Ports, wantPorts) {
+		t.Errorf("Ports = %v, want %v", info.Ports, wantPorts)
+	}
+	wantMounts := []string{"/srv/shop/html:/usr/share/nginx/html (ro)", "shop_cache:/var/cache/nginx"}
+	if !reflect.DeepEqual(info.Mounts, wantMounts) {
+		t.Errorf("Mounts = %v, want %v", info.Mounts, wantMounts)
+	}
+	if info.HealthCheck != "curl -fsS http://localhost/ || exit 1" || info.HealthStatus != "healthy" {
+		t.Errorf("HealthCheck, HealthStatus = %q, %q", info.HealthCheck, info.HealthStatus)
+	}
+}
+
+func TestResolveDockerContainerFromCLI(t *testing.T) {
+	setDockerRoot(t, t.TempDir())
+	inspect, err := os.ReadFile("testdata/docker-inspect.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stubDockerCLI(t, func(args []string) ([]byte, error) {
+		if len(args) > 0 && args[0] == "inspect" {
+			return inspect, nil
+		}
+		return nil, errors.New("unexpected command")
+	})
+
+	info := resolveDockerContainer("9e8d7c6b5a4f")
+	if info == nil {
+		t.Fatal("resolveDockerContainer() = nil")
+	}
+	if got := dockerContainerLabel(info); got != "docker: sql-proxy" {
+		t.Errorf("dockerContainerLabel() = %q", got)
+	}
+	if info.RestartPolicy != "always" {
+		t.Errorf("RestartPolicy = %q", info.RestartPolicy)
+	}
+	if !reflect.DeepEqual(info.Ports, []string{"127.0.0.1:5432->5432/tcp"}) {
+		t.Errorf("Ports = %v", info.Ports)
+	}
+	if !reflect.DeepEqual(info.Mounts, []string{"/etc/sql-proxy:/config (ro)"}) {
+		t.Errorf("Mounts = %v", info.Mounts)
+	}
+	if info.HealthCheck != "" {
+		t.Errorf("HealthCheck = %q, want none", info.HealthCheck)
+	}
+}
+
+func TestResolveContainerByPort(t *testing.T) {
+	t.Run("on-disk state", func(t *testing.T) {
+		setDockerRoot(t, "testdata/docker")
+		stubDockerCLI(t, func(args []string) ([]byte, error) {
+			return nil, errors.New("docker CLI should not be called")
+		})
+
+		m := ResolveContainerByPort(8080)
+		if m == nil {
+			t.Fatal("ResolveContainerByPort(8080) = nil")
+		}
+		if m.ID != testDockerID[:12] || m.ComposeService != "web" || m.RestartPolicy != "unless-stopped" || m.Health != "healthy" {
+			t.Errorf("got %+v", m)
+		}
+		if ResolveContainerByPort(9090) != nil {
+			t.Error("ResolveContainerByPort(9090) matched a container")
+		}
+	})
+
+	t.Run("cli fallback", func(t *testing.T) {
+		setDockerRoot(t, t.TempDir())
+		stubDockerCLI(t, func(args []string) ([]byte, error) {
+			if args[0] == "ps" && strings.Contains(strings.Join(args, " "), "publish=5432") {
+				return []byte("9e8d7c6b5a4f|sql-proxy|cloud-sql-proxy:2.13.0|127.0.0.1:5432->5432/tcp||\n"), nil
+			}
+			return nil, nil
+		})
+
+		m := ResolveContainerByPort(5432)
+		if m == nil {
+			t.Fatal("ResolveContainerByPort(5432) = nil")
+		}
+		if m.Name != "sql-proxy" || m.Ports != "127.0.0.1:5432->5432/tcp" {
+			t.Errorf("got %+v", m)
+		}
+	})
+}
+
+func TestDockerContainerByIP(t *testing.T) {
+	setDockerRoot(t, "testdata/docker")
+
+	if info := dockerContainerByIP("172.18.0.2"); info == nil || info.Name != "shop-web-1" {
+		t.Fatalf("dockerContainerByIP(172.18.0.2) = %+v", info)
+	}
+	if info := dockerContainerByIP("172.18.0.99"); info != nil {
+		t.Fatalf("dockerContainerByIP(172.18.0.99) = %+v, want nil", info)
+	}
+}
+
+func setDockerRoot(t *testing.T, dir string) {
+	t.Helper()
+	orig, origSockets := dockerRoot, dockerSockets
+	dockerRoot = dir
+	// Keep a daemon on the test host out of the picture
+	dockerSockets = func() []string { return nil }
+	t.Cleanup(func() { dockerRoot, dockerSockets = orig, origSockets })
+}
+
+func stubDockerCLI(t *testing.T, fn func(args []string) ([]byte, error)) {
+	t.Helper()
+	orig := dockerCLI
+	dockerCLI = func(_ context.Context, args ...string) ([]byte, error) { return fn(args) }
+	t.Cleanup(func() { dockerCLI = orig })
+}
@@ -0,0 +1,172 @@
+package proc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// engineTimeout bounds a single Engine API request.
+const engineTimeout = 2 * time.Second
+
+// Engine API sockets, in the order they are tried. Variables so tests can
+// point them at a fake server.
+var (
+	dockerSockets = func() []string {
+		return engineSockets("DOCKER_HOST", "/var/run/docker.sock")
+	}
+	podmanSockets = func() []string {
+		var paths []string
+		if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+			paths = append(paths, filepath.Join(dir, "podman", "podman.sock"))
+		}
+		return engineSockets("CONTAINER_HOST", append(paths, "/run/podman/podman.sock")...)
+	}
+)
+
+// engineSockets returns the unix socket named by env (unix:// only) followed
+// by the default paths.
+func engineSockets(env string, defaults ...string) []string {
+	var paths []string
+	if host := os.Getenv(env); strings.HasPrefix(host, "unix://") {
+		paths = append(paths, strings.TrimPrefix(host, "unix://"))
+	}
+	return append(paths, defaults...)
+}
+
+// engineClient is a minimal HTTP-over-unix client for the Docker Engine API.
+// Podman serves the same API on its own socket.
+type engineClient struct {
+	socket string
+	http   *http.Client
+}
+
+// dialEngine returns a client for the first reachable socket, or nil.
+func dialEngine(sockets []string) *engineClient {
+	for _, socket := range sockets {
+		if fi, err := os.Stat(socket); err != nil || fi.Mode()&os.ModeSocket == 0 {
+			continue
+		}
+		return &engineClient{
+			socket: socket,
+			http: &http.Client{
+				Timeout: engineTimeout,
+				Transport: &http.Transport{
+					DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+						var d net.Dialer
+						return d.DialContext(ctx, "unix", socket)
+					},
+				},
+			},
+		}
+	}
+	return nil
+}
+
+// get decodes the JSON response of an API path into v.
+func (e *engineClient) get(path string, v any) error {
+	// The host is ignored by the unix dialer but required by net/http
+	resp, err := e.http.Get("http://engine" + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("engine API %s: %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// inspect returns /containers/{id}/json. id may be a unique prefix.
+func (e *engineClient) inspect(id string) *dockerContainer {
+	var c dockerContainer
+	if err := e.get("/containers/"+url.PathEscape(id)+"/json", &c); err != nil {
+		return nil
+	}
+	return &c
+}
+
+// engineListEntry is the subset of a /containers/json entry witr reads.
+type engineListEntry struct {
+	ID              string `json:"Id"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress string
+		}
+	}
+}
+
+// list returns running containers, optionally narrowed by API filters.
+func (e *engineClient) list(filters map[string][]string) []engineListEntry {
+	path := "/containers/json"
+	if len(filters) > 0 {
+		data, err := json.Marshal(filters)
+		if err != nil {
+			return nil
+		}
+		path += "?filters=" + url.QueryEscape(string(data))
+	}
+	var entries []engineListEntry
+	if err := e.get(path, &entries); err != nil {
+		return nil
+	}
+	return entries
+}
+
+// inspectEngine queries the first reachable socket for a container.
+func inspectEngine(sockets []string, runtime, id string) *model.ContainerInfo {
+	e := dialEngine(sockets)
+	if e == nil || id == "" {
+		return nil
+	}
+	c := e.inspect(id)
+	if c == nil {
+		return nil
+	}
+	info := c.info()
+	info.Runtime = runtime
+	return info
+}
+
+// engineContainerByPort asks the API for a running container publishing port.
+func engineContainerByPort(port int) *model.ContainerInfo {
+	e := dialEngine(dockerSockets())
+	if e == nil {
+		return nil
+	}
+	entries := e.list(map[string][]string{"publish": {fmt.Sprint(port)}})
+	if len(entries) == 0 {
+		return nil
+	}
+	if c := e.inspect(entries[0].ID); c != nil {
+		return c.info()
+	}
+	return nil
+}
+
+// engineContainerByIP asks the API for a running container attached to a network with ip.
+func engineContainerByIP(ip string) *model.ContainerInfo {
+	e := dialEngine(dockerSockets())
+	if e == nil {
+		return nil
+	}
+	for _, entry := range e.list(nil) {
+		for _, n := range entry.NetworkSettings.Networks {
+			if n.IPAddress == ip {
+				if c := e.inspect(entry.ID); c != nil {
+					return c.info()
+				}
+			}
+		}
+	}
+	return nil
+}
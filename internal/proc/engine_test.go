@@ -0,0 +1,132 @@
+package proc
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEngineInspect(t *testing.T) {
+	socket := fakeEngine(t, map[string]string{
+		"/containers/4f9c1e2d3b4a/json": `{
+			"Id": "` + testDockerID + `",
+			"Name": "/shop-web-1",
+			"RestartCount": 3,
+			"Config": {"Image": "nginx:1.27", "Labels": {"com.docker.compose.project": "shop", "com.docker.compose.service": "web"}},
+			"State": {"Running": true, "StartedAt": "2026-03-14T09:26:53.589793238Z", "Health": {"Status": "unhealthy"}},
+			"HostConfig": {"RestartPolicy": {"Name": "on-failure"}}
+		}`,
+	})
+	setDockerRoot(t, t.TempDir())
+	dockerSockets = func() []string { return []string{filepath.Join(filepath.Dir(socket), "missing.sock"), socket} }
+	stubDockerCLI(t, func(args []string) ([]byte, error) {
+		t.Fatalf("docker CLI called with %v; the Engine API should answer", args)
+		return nil, nil
+	})
+
+	info := resolveDockerContainer("4f9c1e2d3b4a")
+	if info == nil {
+		t.Fatal("resolveDockerContainer() = nil")
+	}
+	if got := dockerContainerLabel(info); got != "docker: shop/web (shop-web-1)" {
+		t.Errorf("dockerContainerLabel() = %q", got)
+	}
+	if info.RestartCount != 3 || info.RestartPolicy != "on-failure" || info.HealthStatus != "unhealthy" {
+		t.Errorf("RestartCount, RestartPolicy, HealthStatus = %d, %q, %q", info.RestartCount, info.RestartPolicy, info.HealthStatus)
+	}
+	want := time.Date(2026, 3, 14, 9, 26, 53, 589793238, time.UTC)
+	if !info.StartedAt.Equal(want) {
+		t.Errorf("StartedAt = %v, want %v", info.StartedAt, want)
+	}
+}
+
+func TestEngineContainerByPortAndIP(t *testing.T) {
+	socket := fakeEngine(t, map[string]string{
+		`/containers/json?filters={"publish":["8080"]}`: `[{"Id": "` + testDockerID + `"}]`,
+		`/containers/json?filters={"publish":["9090"]}`: `[]`,
+		"/containers/json": `[
+			{"Id": "0000", "NetworkSettings": {"Networks": {"bridge": {"IPAddress": "172.17.0.2"}}}},
+			{"Id": "` + testDockerID + `", "NetworkSettings": {"Networks": {"shop_default": {"IPAddress": "172.18.0.2"}}}}
+		]`,
+		"/containers/" + testDockerID + "/json": `{
+			"Id": "` + testDockerID + `",
+			"Name": "/shop-web-1",
+			"Config": {"Image": "nginx:1.27"},
+			"State": {"Running": true},
+			"NetworkSettings": {"Ports": {"80/tcp": [{"HostIp": "0.0.0.0", "HostPort": "8080"}]}}
+		}`,
+	})
+	setDockerRoot(t, t.TempDir())
+	dockerSockets = func() []string { return []string{socket} }
+	stubDockerCLI(t, func(args []string) ([]byte, error) {
+		t.Fatalf("docker CLI called with %v; the Engine API should answer", args)
+		return nil, nil
+	})
+
+	m := ResolveContainerByPort(8080)
+	if m == nil {
+		t.Fatal("ResolveContainerByPort(8080) = nil")
+	}
+	if m.ID != testDockerID[:12] || m.Name != "shop-web-1" || m.Ports != "0.0.0.0:8080->80/tcp" {
+		t.Errorf("got %+v", m)
+	}
+	if info := engineContainerByPort(9090); info != nil {
+		t.Errorf("engineContainerByPort(9090) = %+v, want nil", info)
+	}
+	if info := engineContainerByIP("172.18.0.2"); info == nil || info.Name != "shop-web-1" {
+		t.Errorf("engineContainerByIP(172.18.0.2) = %+v", info)
+	}
+}
+
+func TestEngineSockets(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "unix:///run/user/1000/docker.sock")
+	got := engineSockets("DOCKER_HOST", "/var/run/docker.sock")
+	if len(got) != 2 || got[0] != "/run/user/1000/docker.sock" || got[1] != "/var/run/docker.sock" {
+		t.Errorf("engineSockets() = %v", got)
+	}
+
+	t.Setenv("DOCKER_HOST", "tcp://10.0.0.5:2376")
+	if got := engineSockets("DOCKER_HOST", "/var/run/docker.sock"); len(got) != 1 {
+		t.Errorf("engineSockets() with tcp host = %v", got)
+	}
+}
+
+// fakeEngine serves canned JSON bodies keyed by request URI on a temp unix socket.
+func fakeEngine(t *testing.T, responses map[string]string) string {
+	t.Helper()
+	// Unix socket paths are limited to ~100 bytes, too short for t.TempDir() on some hosts
+	dir, err := os.MkdirTemp("", "witr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "engine.sock")
+
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uri := r.URL.Path
+		if r.URL.RawQuery != "" {
+			query, _ := url.QueryUnescape(r.URL.RawQuery)
+			uri += "?" + query
+		}
+		if body, ok := responses[uri]; ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(body))
+			return
+		}
+		http.Error(w, `{"message":"No such container"}`, http.StatusNotFound)
+	}))
+	srv.Listener.Close()
+	srv.Listener = l
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return socket
+}
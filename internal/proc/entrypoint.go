@@ -0,0 +1,257 @@
+package proc
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Options taking a separate value, per runtime. The value must be skipped
+// when looking for the first positional argument.
+var (
+	javaValueOpts   = optionSet("-cp", "-classpath", "--class-path", "-p", "--module-path", "--upgrade-module-path", "--add-modules", "--add-opens", "--add-exports", "--add-reads", "--limit-modules", "--patch-module")
+	pythonValueOpts = optionSet("-W", "-X", "-Q")
+	nodeValueOpts   = optionSet("-r", "--require", "--import", "--loader", "--experimental-loader", "--conditions", "-C", "--title")
+	rubyValueOpts   = optionSet("-I", "-r", "-C", "-E", "-F")
+	perlValueOpts   = optionSet("-I", "-M", "-m", "-x")
+	phpValueOpts    = optionSet("-c", "-d", "-z")
+	shellValueOpts  = optionSet("-o", "-O", "+o", "+O")
+)
+
+// extractEntrypoint derives the jar, class, module or script a runtime process
+// runs from its command line. Relative script paths are resolved against cwd.
+// Returns nil for processes that are not a known runtime.
+func extractEntrypoint(command, cmdline, cwd string) *model.Entrypoint {
+	args := splitCmdline(cmdline)
+	if len(args) == 0 {
+		return nil
+	}
+	runtime := runtimeName(filepath.Base(args[0]))
+	if runtime == "" {
+		runtime = runtimeName(command)
+	}
+
+	var ep *model.Entrypoint
+	switch runtime {
+	case "java":
+		ep = javaEntrypoint(args[1:])
+	case "python", "pypy":
+		ep = pythonEntrypoint(args[1:])
+	case "node":
+		ep = nodeEntrypoint(args[1:])
+	case "bun", "deno":
+		ep = bunDenoEntrypoint(runtime, args[1:])
+	case "npm", "pnpm", "yarn":
+		ep = packageScript(runtime, args[1:])
+	case "ruby":
+		ep = scriptEntrypoint(args[1:], rubyValueOpts, optionSet("-e"))
+	case "perl":
+		ep = scriptEntrypoint(args[1:], perlValueOpts, optionSet("-e", "-E"))
+	case "php":
+		ep = phpEntrypoint(args[1:])
+	case "sh", "bash", "dash", "zsh", "ksh", "mksh", "fish":
+		ep = scriptEntrypoint(args[1:], shellValueOpts, optionSet("-c"))
+	}
+	if ep == nil {
+		return nil
+	}
+	if ep.Kind == "script" {
+		if !filepath.IsAbs(ep.Target) && cwd != "" && cwd != "unknown" && cwd != "invalid" {
+			ep.Target = filepath.Join(cwd, ep.Target)
+		}
+		if runtime != "node" {
+			ep.Shebang = readShebang(ep.Target)
+		}
+	}
+	return ep
+}
+
+// runtimeName maps an executable name to a runtime, ignoring version
+// suffixes such as python3.12 or ruby3.2.
+func runtimeName(name string) string {
+	name = strings.TrimRight(name, "0123456789.")
+	switch name {
+	case "java", "python", "pypy", "node", "nodejs", "bun", "deno", "npm", "pnpm", "yarn", "ruby", "perl", "php",
+		"sh", "bash", "dash", "zsh", "ksh", "mksh", "fish":
+		if name == "nodejs" {
+			return "node"
+		}
+		return name
+	}
+	return ""
+}
+
+func javaEntrypoint(args []string) *model.Entrypoint {
+	var ep *model.Entrypoint
+	appName := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if v, ok := strings.CutPrefix(arg, "-Dspring.application.name="); ok {
+			appName = v
+			continue
+		}
+		if v, ok := strings.CutPrefix(arg, "--spring.application.name="); ok && ep != nil {
+			appName = v
+			continue
+		}
+		if ep != nil {
+			continue
+		}
+		switch {
+		case arg == "-jar" && i+1 < len(args):
+			ep = &model.Entrypoint{Kind: "jar", Target: args[i+1]}
+			i++
+		case (arg == "-m" || arg == "--module") && i+1 < len(args):
+			ep = &model.Entrypoint{Kind: "module", Target: args[i+1]}
+			i++
+		case javaValueOpts[arg]:
+			i++
+		case strings.HasPrefix(arg, "-"):
+		default:
+			ep = &model.Entrypoint{Kind: "class", Target: arg}
+		}
+	}
+	if ep != nil {
+		ep.AppName = appName
+	}
+	return ep
+}
+
+func pythonEntrypoint(args []string) *model.Entrypoint {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-m" && i+1 < len(args):
+			return &model.Entrypoint{Kind: "module", Target: args[i+1]}
+		case strings.HasPrefix(arg, "-m") && len(arg) > 2:
+			return &model.Entrypoint{Kind: "module", Target: arg[2:]}
+		case arg == "-c":
+			return nil
+		case pythonValueOpts[arg]:
+			i++
+		case strings.HasPrefix(arg, "-"):
+		default:
+			return &model.Entrypoint{Kind: "script", Target: arg}
+		}
+	}
+	return nil
+}
+
+func nodeEntrypoint(args []string) *model.Entrypoint {
+	ep := scriptEntrypoint(args, nodeValueOpts, optionSet("-e", "--eval", "-p", "--print"))
+	if ep == nil {
+		return nil
+	}
+	// npm, pnpm and yarn are node scripts themselves; report the package script instead
+	for _, cli := range []string{"npm", "pnpm", "yarn"} {
+		if strings.Contains(ep.Target, "/"+cli+"/") || strings.HasSuffix(ep.Target, "/"+cli) || strings.HasSuffix(ep.Target, "/"+cli+".js") || strings.HasSuffix(ep.Target, "/"+cli+".cjs") {
+			for i, arg := range args {
+				if arg == ep.Target {
+					if pkg := packageScript(cli, args[i+1:]); pkg != nil {
+						return pkg
+					}
+				}
+			}
+		}
+	}
+	return ep
+}
+
+// bunDenoEntrypoint handles "bun index.ts", "bun run dev" and "deno run -A main.ts".
+func bunDenoEntrypoint(runtime string, args []string) *model.Entrypoint {
+	if len(args) > 0 && (args[0] == "run" || args[0] == "task") {
+		sub := args[0]
+		rest := args[1:]
+		for _, arg := range rest {
+			if strings.HasPrefix(arg, "-") {
+				continue
+			}
+			if sub == "task" || filepath.Ext(arg) == "" {
+				return &model.Entrypoint{Kind: "package-script", Target: runtime + " " + sub + " " + arg}
+			}
+			return &model.Entrypoint{Kind: "script", Target: arg}
+		}
+		return nil
+	}
+	return scriptEntrypoint(args, nil, optionSet("-e", "--eval", "-p", "--print"))
+}
+
+// packageScript handles "npm run dev", "npm start", "yarn dev" and friends.
+func packageScript(manager string, args []string) *model.Entrypoint {
+	var positional []string
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) == 0 {
+		return nil
+	}
+	target := manager + " " + positional[0]
+	if (positional[0] == "run" || positional[0] == "run-script") && len(positional) > 1 {
+		target = manager + " run " + positional[1]
+	}
+	return &model.Entrypoint{Kind: "package-script", Target: target}
+}
+
+func phpEntrypoint(args []string) *model.Entrypoint {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-f" && i+1 < len(args) {
+			return &model.Entrypoint{Kind: "script", Target: args[i+1]}
+		}
+	}
+	return scriptEntrypoint(args, phpValueOpts, optionSet("-r", "-S", "-a"))
+}
+
+// scriptEntrypoint returns the first positional argument as a script.
+func scriptEntrypoint(args []string, valueOpts, inline map[string]bool) *model.Entrypoint {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case inline[arg]:
+			return nil
+		case arg == "--":
+			if i+1 < len(args) {
+				return &model.Entrypoint{Kind: "script", Target: args[i+1]}
+			}
+			return nil
+		case valueOpts[arg]:
+			i++
+		case strings.HasPrefix(arg, "-") || strings.HasPrefix(arg, "+"):
+		default:
+			return &model.Entrypoint{Kind: "script", Target: arg}
+		}
+	}
+	return nil
+}
+
+// readShebang returns the interpreter line of a script without the "#!".
+func readShebang(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	buf := make([]byte, 256)
+	n, _ := io.ReadFull(f, buf)
+	head := string(buf[:n])
+	if !strings.HasPrefix(head, "#!") {
+		return ""
+	}
+	line, _, _ := strings.Cut(head[2:], "\n")
+	return strings.TrimSpace(line)
+}
+
+func optionSet(opts ...string) map[string]bool {
+	set := make(map[string]bool, len(opts))
+	for _, o := range opts {
+		set[o] = true
+	}
+	return set
+}
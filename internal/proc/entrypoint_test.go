@@ -0,0 +1,72 @@
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestExtractEntrypoint(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "backup.sh")
+	if err := os.WriteFile(script, []byte("#!/usr/bin/env bash\nset -e\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		cmdline string
+		cwd     string
+		want    *model.Entrypoint
+	}{
+		{"java jar", "java", "/usr/bin/java -Xmx2g -Dspring.application.name=orders -jar /srv/orders/orders.jar --server.port=8080", "/",
+			&model.Entrypoint{Kind: "jar", Target: "/srv/orders/orders.jar", AppName: "orders"}},
+		{"java main class", "java", "java -cp lib/*:app.jar -Dfoo=bar com.acme.Main --verbose", "/srv",
+			&model.Entrypoint{Kind: "class", Target: "com.acme.Main"}},
+		{"java module", "java", "java --module-path mods -m com.acme.app/com.acme.App", "/",
+			&model.Entrypoint{Kind: "module", Target: "com.acme.app/com.acme.App"}},
+		{"java spring arg", "java", "java -jar app.jar --spring.application.name=billing", "/opt",
+			&model.Entrypoint{Kind: "jar", Target: "app.jar", AppName: "billing"}},
+		{"python module", "python3", "/usr/bin/python3.11 -u -m celery -A proj worker", "/srv",
+			&model.Entrypoint{Kind: "module", Target: "celery"}},
+		{"python script relative", "python3", "python3 -W ignore manage.py runserver", "/srv/site",
+			&model.Entrypoint{Kind: "script", Target: "/srv/site/manage.py"}},
+		{"python inline", "python3", "python3 -c print(1)", "/", nil},
+		{"node script", "node", "node --require dotenv/config dist/server.js", "/app",
+			&model.Entrypoint{Kind: "script", Target: "/app/dist/server.js"}},
+		{"node running npm", "node", "node /usr/lib/node_modules/npm/bin/npm-cli.js run dev", "/app",
+			&model.Entrypoint{Kind: "package-script", Target: "npm run dev"}},
+		{"npm start", "npm", "npm start", "/app",
+			&model.Entrypoint{Kind: "package-script", Target: "npm start"}},
+		{"bun run script", "bun", "bun run dev", "/app",
+			&model.Entrypoint{Kind: "package-script", Target: "bun run dev"}},
+		{"deno run", "deno", "deno run -A --watch main.ts", "/app",
+			&model.Entrypoint{Kind: "script", Target: "/app/main.ts"}},
+		{"ruby", "ruby", "ruby -I lib bin/rails server", "/srv/shop",
+			&model.Entrypoint{Kind: "script", Target: "/srv/shop/bin/rails"}},
+		{"php file", "php", "php -d memory_limit=-1 -f artisan queue:work", "/var/www",
+			&model.Entrypoint{Kind: "script", Target: "/var/www/artisan"}},
+		{"shell script with shebang", "bash", "/bin/bash " + script, "/",
+			&model.Entrypoint{Kind: "script", Target: script, Shebang: "/usr/bin/env bash"}},
+		{"shell inline", "sh", "sh -c sleep 10", "/", nil},
+		{"interactive shell", "bash", "-bash", "/root", nil},
+		{"not a runtime", "nginx", "nginx: master process /usr/sbin/nginx", "/", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractEntrypoint(tt.command, tt.cmdline, tt.cwd)
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("extractEntrypoint() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Fatalf("extractEntrypoint() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package proc
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+	"go.yaml.in/yaml/v3"
+)
+
+// envSource is one piece of configuration that sets environment variables
+// for a process.
+type envSource struct {
+	detail string
+	vars   map[string]string
+}
+
+// sets reports whether the source explains name=value. Values the source
+// interpolates at start (compose ${VAR}) match on the name alone.
+func (s envSource) sets(name, value string) bool {
+	v, ok := s.vars[name]
+	return ok && (v == value || strings.Contains(v, "${"))
+}
+
+// EnvOrigins attributes each variable of p's environment: set by the
+// configuration of p's source, inherited unchanged from parent, or added
+// or changed by parent when it launched p. parent may be nil when it has
+// exited or its environment cannot be read.
+func EnvOrigins(p model.Process, parent *model.Process) []model.EnvVar {
+	var parentEnv map[string]string
+	launcher := ""
+	if parent != nil && len(parent.Env) > 0 {
+		parentEnv = envMap(parent.Env)
+		launcher = parent.Command + " (pid " + strconv.Itoa(parent.PID) + ")"
+	}
+	return attributeEnv(p.Env, envSources(p, parent), parentEnv, launcher)
+}
+
+func attributeEnv(env []string, sources []envSource, parentEnv map[string]string, launcher string) []model.EnvVar {
+	vars := make([]model.EnvVar, 0, len(env))
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		v := model.EnvVar{Name: name, Value: value, Origin: model.EnvUnknown}
+		for _, src := range sources {
+			if src.sets(name, value) {
+				v.Origin, v.Detail = model.EnvSource, src.detail
+				break
+			}
+		}
+		if v.Origin == model.EnvUnknown && parentEnv != nil {
+			v.Detail = launcher
+			if pv, ok := parentEnv[name]; ok && pv == value {
+				v.Origin = model.EnvInherited
+			} else {
+				v.Origin, v.Changed = model.EnvLauncher, ok
+			}
+		}
+		vars = append(vars, v)
+	}
+	return vars
+}
+
+// envSources collects the configuration that may have set p's variables,
+// most specific first.
+func envSources(p model.Process, parent *model.Process) []envSource {
+	var sources []envSource
+	if p.Service != "" {
+		sources = append(sources, systemdEnvSources(p.Service)...)
+	}
+	if info := p.ContainerInfo; info != nil && len(info.Env) > 0 {
+		if info.ComposeFiles != "" && info.ComposeService != "" {
+			sources = append(sources, composeEnvSources(info.ComposeFiles, info.ComposeService)...)
+		}
+		sources = append(sources, envSource{
+			detail: info.Runtime + " Config.Env of " + info.Name,
+			vars:   envMap(info.Env),
+		})
+	}
+	sources = append(sources, supervisorEnvSources(p, parent)...)
+	return sources
+}
+
+// systemdEnvSources reads a unit's EnvironmentFile= files, which override
+// its Environment= lines, then the lines themselves.
+func systemdEnvSources(unit string) []envSource {
+	env, files, err := GetSystemdEnvironment(unit)
+	if err != nil {
+		return nil
+	}
+	var sources []envSource
+	for i := len(files) - 1; i >= 0; i-- {
+		if vars := readEnvFile(files[i]); len(vars) > 0 {
+			sources = append(sources, envSource{detail: "systemd EnvironmentFile=" + files[i], vars: vars})
+		}
+	}
+	if len(env) > 0 {
+		sources = append(sources, envSource{detail: "systemd Environment= in " + unit, vars: envMap(env)})
+	}
+	return sources
+}
+
+// composeService is the part of a compose service definition that sets
+// environment. Both fields accept a string, a list or (environment) a map.
+type composeService struct {
+	Environment yaml.Node `yaml:"environment"`
+	EnvFile     yaml.Node `yaml:"env_file"`
+}
+
+// composeEnvSources reads the environment: and env_file: entries of a
+// compose service. files is the comma-separated config_files label.
+func composeEnvSources(files, service string) []envSource {
+	var sources []envSource
+	for _, file := range strings.Split(files, ",") {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var doc struct {
+			Services map[string]composeService `yaml:"services"`
+		}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			continue
+		}
+		svc, ok := doc.Services[service]
+		if !ok {
+			continue
+		}
+		if vars := composeEnvironment(&svc.Environment); len(vars) > 0 {
+			sources = append(sources, envSource{detail: "compose environment: in " + file, vars: vars})
+		}
+		for _, envFile := range yamlStrings(&svc.EnvFile) {
+			if !filepath.IsAbs(envFile) {
+				envFile = filepath.Join(filepath.Dir(file), envFile)
+			}
+			if vars := readEnvFile(envFile); len(vars) > 0 {
+				sources = append(sources, envSource{detail: "compose env_file " + envFile, vars: vars})
+			}
+		}
+	}
+	return sources
+}
+
+// composeEnvironment accepts both the map and the KEY=VALUE list forms.
+// A bare KEY in list form passes the value through from the host.
+func composeEnvironment(n *yaml.Node) map[string]string {
+	vars := make(map[string]string)
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			vars[n.Content[i].Value] = n.Content[i+1].Value
+		}
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			name, value, ok := strings.Cut(item.Value, "=")
+			if !ok {
+				value = "${" + name + "}"
+			}
+			vars[name] = value
+		}
+	}
+	return vars
+}
+
+func yamlStrings(n *yaml.Node) []string {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return []string{n.Value}
+	case yaml.SequenceNode:
+		var out []string
+		for _, item := range n.Content {
+			if item.Kind == yaml.ScalarNode {
+				out = append(out, item.Value)
+			} else if path := item.Content; len(path) >= 2 && path[0].Value == "path" {
+				// long syntax: - path: ./app.env
+				out = append(out, path[1].Value)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// readEnvFile parses a KEY=VALUE file as used by systemd EnvironmentFile=
+// and compose env_file: comments start with # or ;, values may be quoted.
+func readEnvFile(path string) map[string]string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		vars[strings.TrimSpace(name)] = unquote(strings.TrimSpace(value))
+	}
+	return vars
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func envMap(env []string) map[string]string {
+	m := make(map[string]string, len(env))
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		m[name] = value
+	}
+	return m
+}
@@ -0,0 +1,125 @@
+package proc
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestAttributeEnv(t *testing.T) {
+	sources := []envSource{
+		{detail: "systemd EnvironmentFile=/etc/default/app", vars: map[string]string{"DATABASE_URL": "postgres://db/app"}},
+		{detail: "systemd Environment= in app.service", vars: map[string]string{"PORT": "8080", "MODE": "prod"}},
+	}
+	parent := map[string]string{"PATH": "/usr/bin", "LANG": "C.UTF-8", "MODE": "dev"}
+	env := []string{"PATH=/usr/bin", "LANG=en_US.UTF-8", "DATABASE_URL=postgres://db/app", "PORT=9090", "INVOCATION_ID=abc", "MODE=prod", "EMPTY="}
+
+	got := attributeEnv(env, sources, parent, "systemd (pid 1)")
+	want := []model.EnvVar{
+		{Name: "PATH", Value: "/usr/bin", Origin: model.EnvInherited, Detail: "systemd (pid 1)"},
+		{Name: "LANG", Value: "en_US.UTF-8", Origin: model.EnvLauncher, Detail: "systemd (pid 1)", Changed: true},
+		{Name: "DATABASE_URL", Value: "postgres://db/app", Origin: model.EnvSource, Detail: "systemd EnvironmentFile=/etc/default/app"},
+		// The unit says 8080; something later overrode it
+		{Name: "PORT", Value: "9090", Origin: model.EnvLauncher, Detail: "systemd (pid 1)"},
+		{Name: "INVOCATION_ID", Value: "abc", Origin: model.EnvLauncher, Detail: "systemd (pid 1)"},
+		{Name: "MODE", Value: "prod", Origin: model.EnvSource, Detail: "systemd Environment= in app.service"},
+		{Name: "EMPTY", Value: "", Origin: model.EnvLauncher, Detail: "systemd (pid 1)"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("attributeEnv returned %d vars, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("var %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// Without the parent's environment only the source can be attributed
+	got = attributeEnv([]string{"PORT=8080", "HOME=/root"}, sources, nil, "")
+	if got[0].Origin != model.EnvSource || got[1].Origin != model.EnvUnknown || got[1].Detail != "" {
+		t.Errorf("attributeEnv without parent = %+v", got)
+	}
+}
+
+func TestComposeEnvSources(t *testing.T) {
+	dir := filepath.Join("testdata", "envorigin", "compose")
+	file := filepath.Join(dir, "docker-compose.yml")
+
+	sources := composeEnvSources(file, "api")
+	if len(sources) != 2 {
+		t.Fatalf("composeEnvSources returned %d sources, want environment: and env_file", len(sources))
+	}
+	if sources[0].detail != "compose environment: in "+file {
+		t.Errorf("detail = %q", sources[0].detail)
+	}
+	if !sources[0].sets("LOG_LEVEL", "debug") || sources[0].sets("LOG_LEVEL", "info") {
+		t.Error("LOG_LEVEL should match only its configured value")
+	}
+	if !sources[0].sets("API_TOKEN", "anything") {
+		t.Error("interpolated API_TOKEN should match on its name")
+	}
+	if want := "compose env_file " + filepath.Join(dir, "api.env"); sources[1].detail != want {
+		t.Errorf("detail = %q, want %q", sources[1].detail, want)
+	}
+	if !sources[1].sets("REGION", "eu-west-1") || !sources[1].sets("CACHE_TTL", "300") {
+		t.Errorf("env_file vars = %v", sources[1].vars)
+	}
+
+	db := composeEnvSources(file, "db")
+	if len(db) != 1 || !db[0].sets("POSTGRES_PASSWORD", "secret") || !db[0].sets("PGDATA", "/var/lib/postgresql/data") {
+		t.Errorf("list form sources = %+v", db)
+	}
+	if got := composeEnvSources(file, "missing"); got != nil {
+		t.Errorf("unknown service returned %+v", got)
+	}
+}
+
+func TestSupervisorEnvSources(t *testing.T) {
+	defer func(paths []string) { supervisordConfigPaths = paths }(supervisordConfigPaths)
+	dir := filepath.Join("testdata", "envorigin", "supervisor")
+	supervisordConfigPaths = []string{filepath.Join(dir, "supervisord.conf")}
+
+	p := model.Process{Env: []string{"SUPERVISOR_PROCESS_NAME=worker", "SUPERVISOR_GROUP_NAME=worker", "QUEUE=default"}}
+	parent := &model.Process{Command: "supervisord", Cmdline: "/usr/bin/python3 /usr/bin/supervisord -n", Env: []string{"HOME=/root"}}
+
+	sources := supervisorEnvSources(p, parent)
+	if len(sources) != 2 {
+		t.Fatalf("supervisorEnvSources returned %d sources, want [program:worker] and [supervisord]", len(sources))
+	}
+	program := sources[0]
+	if want := "supervisord environment= in [program:worker] of " + filepath.Join(dir, "conf.d", "worker.conf"); program.detail != want {
+		t.Errorf("detail = %q, want %q", program.detail, want)
+	}
+	for name, value := range map[string]string{
+		"DATABASE_URL": "postgres://app@db/app",
+		"APP_HOME":     filepath.Join(dir, "conf.d"),
+		"QUEUE":        "default",
+		"HOME_COPY":    "/root",
+	} {
+		if !program.sets(name, value) {
+			t.Errorf("program source does not set %s=%s (vars %v)", name, value, program.vars)
+		}
+	}
+	if !sources[1].sets("TZ", "UTC") {
+		t.Errorf("[supervisord] environment = %v", sources[1].vars)
+	}
+
+	if got := supervisorEnvSources(model.Process{Env: []string{"QUEUE=default"}}, parent); got != nil {
+		t.Errorf("process not run by supervisord returned %+v", got)
+	}
+}
+
+func TestSupervisordConfigArg(t *testing.T) {
+	tests := map[string]string{
+		"/usr/bin/supervisord -n -c /etc/supervisor/supervisord.conf": "/etc/supervisor/supervisord.conf",
+		"supervisord --configuration=/srv/sv.conf":                    "/srv/sv.conf",
+		"supervisord -c/srv/sv.conf":                                  "/srv/sv.conf",
+		"/usr/bin/python3 /usr/bin/supervisord -n":                    "",
+	}
+	for cmdline, want := range tests {
+		if got := supervisordConfigArg(cmdline); got != want {
+			t.Errorf("supervisordConfigArg(%q) = %q, want %q", cmdline, got, want)
+		}
+	}
+}
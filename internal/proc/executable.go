@@ -0,0 +1,134 @@
+package proc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"debug/buildinfo"
+	"debug/elf"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ReadExecutableInfo inspects the executable a process runs. On Linux the
+// image is read through /proc/<pid>/exe, so a replaced or deleted file still
+// reports what is actually running.
+func ReadExecutableInfo(pid int, path string, startedAt time.Time) *model.ExecutableInfo {
+	image := fmt.Sprintf("/proc/%d/exe", pid)
+	fi, err := os.Stat(image)
+	if err != nil {
+		image = path
+		if fi, err = os.Stat(image); err != nil {
+			return nil
+		}
+	}
+
+	info := &model.ExecutableInfo{
+		Path:    path,
+		Size:    fi.Size(),
+		ModTime: fi.ModTime(),
+	}
+	if !startedAt.IsZero() {
+		info.ModifiedAfterStart = fi.ModTime().After(startedAt)
+	}
+	if sum, err := sha256File(image); err == nil {
+		info.SHA256 = sum
+	}
+	if f, err := elf.Open(image); err == nil {
+		info.BuildID, info.Interpreter, info.Linkage = elfDetails(f)
+		f.Close()
+	}
+	if bi, err := buildinfo.ReadFile(image); err == nil {
+		gi := &model.GoBuildInfo{
+			GoVersion:   bi.GoVersion,
+			Path:        bi.Path,
+			MainModule:  bi.Main.Path,
+			MainVersion: bi.Main.Version,
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				gi.VCSRevision = s.Value
+			case "vcs.time":
+				gi.VCSTime = s.Value
+			case "vcs.modified":
+				gi.VCSModified = s.Value == "true"
+			}
+		}
+		info.Go = gi
+	}
+	return info
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// elfDetails returns the GNU build-id, the PT_INTERP interpreter and the linkage.
+func elfDetails(f *elf.File) (buildID, interp, linkage string) {
+	hasDynamic := false
+	for _, p := range f.Progs {
+		switch p.Type {
+		case elf.PT_INTERP:
+			if data, err := io.ReadAll(p.Open()); err == nil {
+				interp = string(bytes.TrimRight(data, "\x00"))
+			}
+		case elf.PT_DYNAMIC:
+			hasDynamic = true
+		case elf.PT_NOTE:
+			if buildID == "" {
+				if data, err := io.ReadAll(p.Open()); err == nil {
+					buildID = gnuBuildID(data, f.ByteOrder)
+				}
+			}
+		}
+	}
+	switch {
+	case interp != "":
+		linkage = "dynamic"
+	case hasDynamic:
+		linkage = "static-pie"
+	default:
+		linkage = "static"
+	}
+	return buildID, interp, linkage
+}
+
+// gnuBuildID walks ELF notes for NT_GNU_BUILD_ID. Each note is namesz, descsz
+// and type, followed by the name and descriptor, both padded to 4 bytes.
+// Sizes come from the file and are untrusted, so offsets are computed in
+// uint64 and checked against the bytes left before slicing.
+func gnuBuildID(notes []byte, order binary.ByteOrder) string {
+	const ntGNUBuildID = 3
+	align := func(n uint64) uint64 { return (n + 3) &^ 3 }
+	for len(notes) >= 12 {
+		namesz := uint64(order.Uint32(notes[0:4]))
+		descsz := uint64(order.Uint32(notes[4:8]))
+		typ := order.Uint32(notes[8:12])
+		nameEnd := 12 + align(namesz)
+		descEnd := nameEnd + align(descsz)
+		if descEnd > uint64(len(notes)) {
+			return ""
+		}
+		name := notes[12 : 12+namesz]
+		if typ == ntGNUBuildID && string(bytes.TrimRight(name, "\x00")) == "GNU" {
+			return hex.EncodeToString(notes[nameEnd : nameEnd+descsz])
+		}
+		notes = notes[descEnd:]
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package proc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestReadExecutableInfo(t *testing.T) {
+	self, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	data, err := os.ReadFile(self)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+
+	info := ReadExecutableInfo(os.Getpid(), self, time.Now())
+	if info == nil {
+		t.Fatal("ReadExecutableInfo() = nil")
+	}
+	if info.Path != self || info.Size != int64(len(data)) {
+		t.Errorf("Path, Size = %q, %d", info.Path, info.Size)
+	}
+	if info.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("SHA256 = %s", info.SHA256)
+	}
+	if info.ModifiedAfterStart {
+		t.Error("ModifiedAfterStart = true for a binary built before the test started")
+	}
+	if info.Go == nil {
+		t.Fatal("Go build info missing from a Go test binary")
+	}
+	if info.Go.GoVersion != runtime.Version() {
+		t.Errorf("GoVersion = %q, want %q", info.Go.GoVersion, runtime.Version())
+	}
+	if runtime.GOOS == "linux" && info.Linkage == "" {
+		t.Error("Linkage empty for an ELF binary")
+	}
+}
+
+func TestGNUBuildID(t *testing.T) {
+	note := func(name string, typ uint32, desc []byte) []byte {
+		pad := func(b []byte) []byte {
+			for len(b)%4 != 0 {
+				b = append(b, 0)
+			}
+			return b
+		}
+		n := []byte(name + "\x00")
+		buf := make([]byte, 12)
+		binary.LittleEndian.PutUint32(buf[0:], uint32(len(n)))
+		binary.LittleEndian.PutUint32(buf[4:], uint32(len(desc)))
+		binary.LittleEndian.PutUint32(buf[8:], typ)
+		buf = append(buf, pad(n)...)
+		return append(buf, pad(append([]byte(nil), desc...))...)
+	}
+	// A header claiming sizes far beyond the data, followed by a few bytes
+	malformed := func(namesz, descsz uint32) []byte {
+		buf := make([]byte, 24)
+		binary.LittleEndian.PutUint32(buf[0:], namesz)
+		binary.LittleEndian.PutUint32(buf[4:], descsz)
+		binary.LittleEndian.PutUint32(buf[8:], 3)
+		copy(buf[12:], "GNU\x00")
+		return buf
+	}
+	id := []byte{0x82, 0xa0, 0xac, 0xb9, 0x2e, 0x62, 0x88, 0xfb, 0x69, 0x85}
+
+	tests := []struct {
+		name  string
+		notes []byte
+		want  string
+	}{
+		{"build-id only", note("GNU", 3, id), "82a0acb92e6288fb6985"},
+		{"after ABI tag", append(note("GNU", 1, []byte{0, 0, 0, 0, 3, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0}), note("GNU", 3, id)...), "82a0acb92e6288fb6985"},
+		{"go note only", note("Go", 4, []byte("abc/def")), ""},
+		{"truncated", note("GNU", 3, id)[:20], ""},
+		{"namesz overflow", malformed(0xFFFFFFFF, 0), ""},
+		{"descsz overflow", malformed(4, 0xFFFFFFFD), ""},
+		{"both overflow", malformed(0xFFFFFFFE, 0xFFFFFFFF), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gnuBuildID(tt.notes, binary.LittleEndian); got != tt.want {
+				t.Fatalf("gnuBuildID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
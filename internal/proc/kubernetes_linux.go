@@ -0,0 +1,312 @@
+//go:build linux
+
+package proc
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Local kubelet and CRI state locations. Variables so tests can point them at fixtures.
+var (
+	kubeletPodsDir   = "/var/lib/kubelet/pods"
+	podLogsDir       = "/var/log/pods"
+	containerLogsDir = "/var/log/containers"
+)
+
+// resolveKubePod builds the pod identity of a kubepods process from its cgroup
+// and the kubelet's on-disk state. Returns nil if the cgroup carries no pod UID.
+func resolveKubePod(cgroup string) *model.KubernetesPod {
+	uid, qos, containerID := parseKubeCgroup(cgroup)
+	if uid == "" {
+		return nil
+	}
+
+	pod := &model.KubernetesPod{
+		UID:         uid,
+		QoSClass:    qos,
+		ContainerID: containerID,
+	}
+
+	logDir := ""
+	if entries, err := os.ReadDir(podLogsDir); err == nil {
+		for _, e := range entries {
+			// <namespace>_<pod>_<uid>; namespaces and pod names cannot contain '_'
+			parts := strings.Split(e.Name(), "_")
+			if len(parts) == 3 && parts[2] == uid {
+				pod.Namespace = parts[0]
+				pod.Name = parts[1]
+				logDir = filepath.Join(podLogsDir, e.Name())
+				break
+			}
+		}
+	}
+
+	if pod.Name == "" {
+		pod.Name = podNameFromEtcHosts(filepath.Join(kubeletPodsDir, uid, "etc-hosts"))
+	}
+
+	if containerID != "" && pod.Name != "" && pod.Namespace != "" {
+		pod.Container = containerNameFromLogLink(pod.Name, pod.Namespace, containerID)
+	}
+	if pod.Container == "" {
+		// A single container dir is unambiguous even without the ID mapping
+		if names := listDirs(filepath.Join(kubeletPodsDir, uid, "containers")); len(names) == 1 {
+			pod.Container = names[0]
+		} else if logDir != "" {
+			if names := listDirs(logDir); len(names) == 1 {
+				pod.Container = names[0]
+			}
+		}
+	}
+
+	if logDir != "" && pod.Container != "" {
+		pod.RestartCount = restartCountFromLogs(filepath.Join(logDir, pod.Container))
+	}
+
+	if containerID != "" {
+		if labels := crictlPodLabels(containerID); len(labels) > 0 {
+			pod.OwnerKind, pod.OwnerName = kubeOwnerFromLabels(labels, pod.Name)
+		}
+	}
+
+	return pod
+}
+
+// kubePodLabel formats a pod identity for the Container field.
+func kubePodLabel(pod *model.KubernetesPod) string {
+	var parts []string
+	if pod.Namespace != "" {
+		parts = append(parts, pod.Namespace)
+	}
+	if pod.Name != "" {
+		parts = append(parts, pod.Name)
+	}
+	if pod.Container != "" {
+		parts = append(parts, pod.Container)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "k8s: " + strings.Join(parts, "/")
+}
+
+// parseKubeCgroup extracts the pod UID, QoS class and container ID from a
+// kubepods cgroup path. Both the systemd and cgroupfs drivers are handled:
+//
+//	/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod<uid>.slice/cri-containerd-<id>.scope
+//	/kubepods/besteffort/pod<uid>/<id>
+func parseKubeCgroup(cgroup string) (uid, qos, containerID string) {
+	for _, line := range strings.Split(cgroup, "\n") {
+		idx := strings.Index(line, "kubepods")
+		if idx == -1 {
+			continue
+		}
+		segments := strings.Split(line[idx:], "/")
+		for i, seg := range segments {
+			lower := strings.ToLower(seg)
+			switch {
+			case strings.Contains(lower, "besteffort"):
+				qos = "BestEffort"
+			case strings.Contains(lower, "burstable"):
+				qos = "Burstable"
+			}
+
+			if podIdx := strings.LastIndex(seg, "pod"); podIdx != -1 && uid == "" {
+				candidate := strings.TrimSuffix(seg[podIdx+3:], ".slice")
+				// systemd escapes '-' in the UID as '_'
+				candidate = strings.ReplaceAll(candidate, "_", "-")
+				if isPodUID(candidate) {
+					uid = candidate
+					if i+1 < len(segments) {
+						containerID = findLongHexID(segments[i+1])
+					}
+				}
+			}
+		}
+		if uid != "" {
+			if qos == "" {
+				qos = "Guaranteed"
+			}
+			return uid, qos, containerID
+		}
+	}
+	return "", "", ""
+}
+
+// isPodUID reports whether s looks like a Kubernetes UID (RFC 4122 UUID).
+func isPodUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i, c := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f')) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// podNameFromEtcHosts returns the pod hostname from the kubelet-managed hosts
+// file. The kubelet appends "<pod IP>\t<hostname>" after the loopback entries.
+func podNameFromEtcHosts(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	name := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "127.0.0.1", "::1", "fe00::0", "fe00::", "fe00::1", "fe00::2", "ff00::0", "ff02::1", "ff02::2":
+			continue
+		}
+		name = fields[1]
+	}
+	// Hostnames may be FQDNs when the pod sets a subdomain
+	if dot := strings.Index(name, "."); dot != -1 {
+		name = name[:dot]
+	}
+	return name
+}
+
+// containerNameFromLogLink maps a container ID to its name through the CRI
+// log symlinks: /var/log/containers/<pod>_<namespace>_<container>-<id>.log
+func containerNameFromLogLink(pod, namespace, containerID string) string {
+	entries, err := os.ReadDir(containerLogsDir)
+	if err != nil {
+		return ""
+	}
+	prefix := pod + "_" + namespace + "_"
+	suffix := "-" + containerID + ".log"
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, prefix) && strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(strings.TrimPrefix(name, prefix), suffix)
+		}
+	}
+	return ""
+}
+
+// restartCountFromLogs returns the highest attempt number among the CRI log
+// files (0.log, 1.log, 1.log.20240101-120000.gz, ...) of a container.
+func restartCountFromLogs(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	highest := 0
+	for _, e := range entries {
+		attempt, _, ok := strings.Cut(e.Name(), ".log")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(attempt); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return highest
+}
+
+func listDirs(path string) []string {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// crictlPodLabels returns the labels of the pod sandbox owning a container.
+func crictlPodLabels(containerID string) map[string]string {
+	if _, err := exec.LookPath("crictl"); err != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "crictl", "inspect", "-o", "go-template", "--template", "{{.info.sandboxID}}", containerID).Output()
+	if err != nil {
+		return nil
+	}
+	sandboxID := strings.TrimSpace(string(out))
+	if sandboxID == "" {
+		return nil
+	}
+
+	out, err = exec.CommandContext(ctx, "crictl", "inspectp", "-o", "go-template", "--template",
+		"{{range $k, $v := .status.labels}}{{$k}}={{$v}}{{\"\\n\"}}{{end}}", sandboxID).Output()
+	if err != nil {
+		return nil
+	}
+
+	labels := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+// kubeOwnerFromLabels infers the controlling workload from the labels
+// controllers stamp onto their pods.
+func kubeOwnerFromLabels(labels map[string]string, podName string) (kind, name string) {
+	if job := labels["batch.kubernetes.io/job-name"]; job != "" {
+		return "Job", job
+	}
+	if job := labels["job-name"]; job != "" {
+		return "Job", job
+	}
+	if hash := labels["pod-template-hash"]; hash != "" {
+		// Deployment pods are named <deployment>-<hash>-<suffix>
+		if idx := strings.Index(podName, "-"+hash+"-"); idx > 0 {
+			return "Deployment", podName[:idx]
+		}
+		return "ReplicaSet", trimPodSuffix(podName)
+	}
+	if labels["controller-revision-hash"] != "" {
+		if labels["statefulset.kubernetes.io/pod-name"] != "" {
+			return "StatefulSet", trimPodSuffix(podName)
+		}
+		if labels["pod-template-generation"] != "" {
+			return "DaemonSet", trimPodSuffix(podName)
+		}
+	}
+	return "", ""
+}
+
+// trimPodSuffix drops the generated ordinal or random suffix from a pod name.
+func trimPodSuffix(podName string) string {
+	if idx := strings.LastIndex(podName, "-"); idx > 0 {
+		return podName[:idx]
+	}
+	return podName
+}
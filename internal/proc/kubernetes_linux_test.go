@@ -0,0 +1,162 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testPodUID      = "6f1c2a9e-1b2c-4d5e-8f90-a1b2c3d4e5f6"
+	testContainerID = "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+)
+
+func TestParseKubeCgroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		cgroup  string
+		wantUID string
+		wantQoS string
+		wantID  string
+	}{
+		{
+			name:    "systemd driver burstable",
+			cgroup:  "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod6f1c2a9e_1b2c_4d5e_8f90_a1b2c3d4e5f6.slice/cri-containerd-" + testContainerID + ".scope\n",
+			wantUID: testPodUID,
+			wantQoS: "Burstable",
+			wantID:  testContainerID,
+		},
+		{
+			name:    "cgroupfs driver besteffort",
+			cgroup:  "0::/kubepods/besteffort/pod" + testPodUID + "/" + testContainerID + "\n",
+			wantUID: testPodUID,
+			wantQoS: "BestEffort",
+			wantID:  testContainerID,
+		},
+		{
+			name:    "guaranteed has no qos slice",
+			cgroup:  "0::/kubepods.slice/kubepods-pod6f1c2a9e_1b2c_4d5e_8f90_a1b2c3d4e5f6.slice/crio-" + testContainerID + ".scope\n",
+			wantUID: testPodUID,
+			wantQoS: "Guaranteed",
+			wantID:  testContainerID,
+		},
+		{
+			name:   "not a pod",
+			cgroup: "0::/system.slice/docker-" + testContainerID + ".scope\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid, qos, id := parseKubeCgroup(tt.cgroup)
+			if uid != tt.wantUID || qos != tt.wantQoS || id != tt.wantID {
+				t.Fatalf("parseKubeCgroup() = (%q, %q, %q), want (%q, %q, %q)", uid, qos, id, tt.wantUID, tt.wantQoS, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestResolveKubePodFromNodeState(t *testing.T) {
+	root := t.TempDir()
+	setKubeDirs(t, root)
+
+	// /var/log/pods/<ns>_<pod>_<uid>/<container>/<attempt>.log
+	podLogDir := filepath.Join(podLogsDir, "shop_web-7c5ddbdf54-x2k9q_"+testPodUID)
+	mustWrite(t, filepath.Join(podLogDir, "nginx", "0.log"), "")
+	mustWrite(t, filepath.Join(podLogDir, "nginx", "2.log"), "")
+	mustWrite(t, filepath.Join(podLogDir, "nginx", "1.log.20240101-120000.gz"), "")
+	mustWrite(t, filepath.Join(podLogDir, "sidecar", "0.log"), "")
+
+	// /var/log/containers/<pod>_<ns>_<container>-<id>.log
+	mustWrite(t, filepath.Join(containerLogsDir, "web-7c5ddbdf54-x2k9q_shop_nginx-"+testContainerID+".log"), "")
+
+	cgroup := "0::/kubepods/burstable/pod" + testPodUID + "/" + testContainerID + "\n"
+	pod := resolveKubePod(cgroup)
+	if pod == nil {
+		t.Fatal("resolveKubePod() = nil")
+	}
+	if pod.Namespace != "shop" || pod.Name != "web-7c5ddbdf54-x2k9q" || pod.Container != "nginx" {
+		t.Fatalf("got %s/%s/%s, want shop/web-7c5ddbdf54-x2k9q/nginx", pod.Namespace, pod.Name, pod.Container)
+	}
+	if pod.RestartCount != 2 {
+		t.Errorf("RestartCount = %d, want 2", pod.RestartCount)
+	}
+	if pod.QoSClass != "Burstable" {
+		t.Errorf("QoSClass = %q, want Burstable", pod.QoSClass)
+	}
+	if got := kubePodLabel(pod); got != "k8s: shop/web-7c5ddbdf54-x2k9q/nginx" {
+		t.Errorf("kubePodLabel() = %q", got)
+	}
+}
+
+func TestResolveKubePodFromEtcHosts(t *testing.T) {
+	root := t.TempDir()
+	setKubeDirs(t, root)
+
+	hosts := "# Kubernetes-managed hosts file.\n127.0.0.1\tlocalhost\n::1\tlocalhost ip6-localhost ip6-loopback\nfe00::0\tip6-localnet\n10.244.1.7\tbatch-28457100-zt4kd\n"
+	mustWrite(t, filepath.Join(kubeletPodsDir, testPodUID, "etc-hosts"), hosts)
+	if err := os.MkdirAll(filepath.Join(kubeletPodsDir, testPodUID, "containers", "worker"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	pod := resolveKubePod("0::/kubepods/pod" + testPodUID + "/" + testContainerID + "\n")
+	if pod == nil {
+		t.Fatal("resolveKubePod() = nil")
+	}
+	if pod.Name != "batch-28457100-zt4kd" {
+		t.Errorf("Name = %q", pod.Name)
+	}
+	if pod.Container != "worker" {
+		t.Errorf("Container = %q, want worker", pod.Container)
+	}
+	if pod.QoSClass != "Guaranteed" {
+		t.Errorf("QoSClass = %q, want Guaranteed", pod.QoSClass)
+	}
+}
+
+func TestKubeOwnerFromLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		pod      string
+		wantKind string
+		wantName string
+	}{
+		{"deployment", map[string]string{"pod-template-hash": "7c5ddbdf54"}, "web-7c5ddbdf54-x2k9q", "Deployment", "web"},
+		{"statefulset", map[string]string{"controller-revision-hash": "db-5d8f", "statefulset.kubernetes.io/pod-name": "db-0"}, "db-0", "StatefulSet", "db"},
+		{"daemonset", map[string]string{"controller-revision-hash": "6b9", "pod-template-generation": "3"}, "node-exporter-abcde", "DaemonSet", "node-exporter"},
+		{"job", map[string]string{"batch.kubernetes.io/job-name": "backup-28457100"}, "backup-28457100-zt4kd", "Job", "backup-28457100"},
+		{"bare pod", map[string]string{"app": "debug"}, "debug", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, name := kubeOwnerFromLabels(tt.labels, tt.pod)
+			if kind != tt.wantKind || name != tt.wantName {
+				t.Fatalf("kubeOwnerFromLabels() = (%q, %q), want (%q, %q)", kind, name, tt.wantKind, tt.wantName)
+			}
+		})
+	}
+}
+
+func setKubeDirs(t *testing.T, root string) {
+	t.Helper()
+	origPods, origPodLogs, origContainerLogs := kubeletPodsDir, podLogsDir, containerLogsDir
+	kubeletPodsDir = filepath.Join(root, "var/lib/kubelet/pods")
+	podLogsDir = filepath.Join(root, "var/log/pods")
+	containerLogsDir = filepath.Join(root, "var/log/containers")
+	t.Cleanup(func() {
+		kubeletPodsDir, podLogsDir, containerLogsDir = origPods, origPodLogs, origContainerLogs
+	})
+}
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,84 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// System container state locations. Variables so tests can point them at fixtures.
+var (
+	lxcPath          = "/var/lib/lxc"
+	lxdSnapPath      = "/var/snap/lxd/common/lxd/containers"
+	lxdPath          = "/var/lib/lxd/containers"
+	incusPath        = "/var/lib/incus/containers"
+	nspawnConfigDirs = []string{"/etc/systemd/nspawn", "/run/systemd/nspawn", "/var/lib/machines"}
+)
+
+// parseLXCCgroup returns the container name from an LXC, LXD or Incus cgroup:
+//
+//	/lxc.payload.<name>/...   (LXC 4+, LXD, Incus)
+//	/lxc.payload/<name>/...   (LXC 3 with cgroup v2)
+//	/lxc/<name>/...           (legacy cgroup v1 layout)
+//
+// The monitor's own cgroup (lxc.monitor.<name>) is host-side and not matched.
+func parseLXCCgroup(cgroup string) string {
+	for _, line := range strings.Split(cgroup, "\n") {
+		for _, prefix := range []string{"/lxc.payload.", "/lxc.payload/", "/lxc/"} {
+			idx := strings.Index(line, prefix)
+			if idx == -1 {
+				continue
+			}
+			name := line[idx+len(prefix):]
+			if slash := strings.Index(name, "/"); slash != -1 {
+				name = name[:slash]
+			}
+			if name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// resolveLXCContainer identifies which manager owns an LXC container by
+// looking for its state directory, and reports the config file.
+func resolveLXCContainer(name string) *model.ContainerInfo {
+	candidates := []struct {
+		runtime string
+		config  string
+	}{
+		{"incus", filepath.Join(incusPath, name, "backup.yaml")},
+		{"lxd", filepath.Join(lxdSnapPath, name, "backup.yaml")},
+		{"lxd", filepath.Join(lxdPath, name, "backup.yaml")},
+		{"lxc", filepath.Join(lxcPath, name, "config")},
+	}
+	for _, c := range candidates {
+		if _, err := os.Stat(filepath.Dir(c.config)); err != nil {
+			continue
+		}
+		info := &model.ContainerInfo{Runtime: c.runtime, Name: name}
+		if _, err := os.Stat(c.config); err == nil {
+			info.ConfigPath = c.config
+		}
+		return info
+	}
+	return &model.ContainerInfo{Runtime: "lxc", Name: name}
+}
+
+// resolveNspawnMachine reports the .nspawn settings file of a machine, if any.
+func resolveNspawnMachine(name string) *model.ContainerInfo {
+	info := &model.ContainerInfo{Runtime: "nspawn", Name: name}
+	for _, dir := range nspawnConfigDirs {
+		path := filepath.Join(dir, name+".nspawn")
+		if _, err := os.Stat(path); err == nil {
+			info.ConfigPath = path
+			break
+		}
+	}
+	return info
+}
@@ -0,0 +1,79 @@
+//go:build linux
+
+package proc
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseLXCCgroup(t *testing.T) {
+	tests := []struct {
+		name   string
+		cgroup string
+		want   string
+	}{
+		{"lxd payload", "0::/lxc.payload.web01/system.slice/nginx.service\n", "web01"},
+		{"payload init", "0::/lxc.payload.db\n", "db"},
+		{"lxc 3 payload dir", "0::/lxc.payload/build/init.scope\n", "build"},
+		{"legacy v1", "12:pids:/lxc/ci-runner\n11:memory:/lxc/ci-runner\n", "ci-runner"},
+		{"monitor is host side", "0::/lxc.monitor.web01\n", ""},
+		{"docker", "0::/system.slice/docker-" + testContainerID + ".scope\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLXCCgroup(tt.cgroup); got != tt.want {
+				t.Fatalf("parseLXCCgroup() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSystemContainers(t *testing.T) {
+	root := t.TempDir()
+	origLXC, origSnap, origLXD, origIncus, origNspawn := lxcPath, lxdSnapPath, lxdPath, incusPath, nspawnConfigDirs
+	lxcPath = filepath.Join(root, "var/lib/lxc")
+	lxdSnapPath = filepath.Join(root, "var/snap/lxd/common/lxd/containers")
+	lxdPath = filepath.Join(root, "var/lib/lxd/containers")
+	incusPath = filepath.Join(root, "var/lib/incus/containers")
+	nspawnConfigDirs = []string{filepath.Join(root, "etc/systemd/nspawn"), filepath.Join(root, "var/lib/machines")}
+	t.Cleanup(func() {
+		lxcPath, lxdSnapPath, lxdPath, incusPath, nspawnConfigDirs = origLXC, origSnap, origLXD, origIncus, origNspawn
+	})
+
+	mustWrite(t, filepath.Join(lxdSnapPath, "web01", "backup.yaml"), "container:\n  name: web01\n")
+	mustWrite(t, filepath.Join(incusPath, "db", "backup.yaml"), "container:\n  name: db\n")
+	mustWrite(t, filepath.Join(lxcPath, "build", "config"), "lxc.uts.name = build\n")
+	mustWrite(t, filepath.Join(root, "var/lib/machines", "debian.nspawn"), "[Exec]\nBoot=yes\n")
+
+	tests := []struct {
+		name        string
+		got         func() (string, string)
+		wantRuntime string
+		wantConfig  string
+	}{
+		{"lxd snap", lxcInfo("web01"), "lxd", filepath.Join(lxdSnapPath, "web01", "backup.yaml")},
+		{"incus", lxcInfo("db"), "incus", filepath.Join(incusPath, "db", "backup.yaml")},
+		{"classic lxc", lxcInfo("build"), "lxc", filepath.Join(lxcPath, "build", "config")},
+		{"unknown lxc", lxcInfo("gone"), "lxc", ""},
+		{"nspawn", func() (string, string) {
+			info := resolveNspawnMachine("debian")
+			return info.Runtime, info.ConfigPath
+		}, "nspawn", filepath.Join(root, "var/lib/machines", "debian.nspawn")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime, config := tt.got()
+			if runtime != tt.wantRuntime || config != tt.wantConfig {
+				t.Fatalf("got (%q, %q), want (%q, %q)", runtime, config, tt.wantRuntime, tt.wantConfig)
+			}
+		})
+	}
+}
+
+func lxcInfo(name string) func() (string, string) {
+	return func() (string, string) {
+		info := resolveLXCContainer(name)
+		return info.Runtime, info.ConfigPath
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"bufio"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -29,50 +31,71 @@ var stateMap = map[string]string{
 }
 
 func readSockets() (map[string]model.Socket, error) {
-	sockets := make(map[string]model.Socket)
+	return readSocketTables("/proc/net"), nil
+}
 
-	parse := func(path, proto string, ipv6 bool) {
-		f, err := os.Open(path)
+// readSocketTables reads the TCP and UDP tables of a network namespace,
+// /proc/net for witr's own or /proc/<pid>/net for a process's, keyed by
+// socket inode.
+func readSocketTables(dir string) map[string]model.Socket {
+	sockets := make(map[string]model.Socket)
+	for _, table := range []struct {
+		file, proto string
+		ipv6        bool
+	}{
+		{"tcp", "TCP", false},
+		{"tcp6", "TCP6", true},
+		{"udp", "UDP", false},
+		{"udp6", "UDP6", true},
+	} {
+		f, err := os.Open(filepath.Join(dir, table.file))
 		if err != nil {
-			return
+			continue
 		}
-		defer f.Close()
+		parseSocketTable(f, table.proto, table.ipv6, sockets)
+		f.Close()
+	}
+	return sockets
+}
 
-		scanner := bufio.NewScanner(f)
-		scanner.Scan() // skip header
+func parseSocketTable(r io.Reader, proto string, ipv6 bool, sockets map[string]model.Socket) {
+	scanner := bufio.NewScanner(r)
+	scanner.Scan() // skip header
 
-		for scanner.Scan() {
-			fields := strings.Fields(scanner.Text())
-			if len(fields) < 10 {
-				continue
-			}
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
 
-			local := fields[1]
-			stateHex := fields[3]
-			inode := fields[9]
+		local := fields[1]
+		remote := fields[2]
+		stateHex := fields[3]
+		inode := fields[9]
 
-			state, ok := stateMap[stateHex]
-			if !ok {
-				state = "UNKNOWN"
-			}
+		state, ok := stateMap[stateHex]
+		if !ok {
+			state = "UNKNOWN"
+		}
 
-			addr, port := parseAddr(local, ipv6)
-			sockets[inode] = model.Socket{
-				Inode:    inode,
-				Port:     port,
-				Address:  addr,
-				State:    state,
-				Protocol: proto,
-			}
+		addr, port := parseAddr(local, ipv6)
+		sock := model.Socket{
+			Inode:    inode,
+			Port:     port,
+			Address:  addr,
+			State:    state,
+			Protocol: proto,
 		}
+		if remoteAddr, remotePort := parseAddr(remote, ipv6); remotePort != 0 {
+			sock.RemoteAddress, sock.RemotePort = remoteAddr, remotePort
+		}
+		// tx_queue:rx_queue, in hex
+		if tx, rx, ok := strings.Cut(fields[4], ":"); ok {
+			sock.TxQueue, _ = strconv.ParseUint(tx, 16, 64)
+			sock.RxQueue, _ = strconv.ParseUint(rx, 16, 64)
+		}
+		sockets[inode] = sock
 	}
-
-	parse("/proc/net/tcp", "TCP", false)
-	parse("/proc/net/tcp6", "TCP6", true)
-	parse("/proc/net/udp", "UDP", false)
-	parse("/proc/net/udp6", "UDP6", true)
-
-	return sockets, nil
 }
 
 func parseAddr(raw string, ipv6 bool) (string, int) {
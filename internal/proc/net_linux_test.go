@@ -6,7 +6,10 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net"
+	"strings"
 	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 func encodeProcNetTCP6(ip net.IP, port int) string {
@@ -137,3 +140,51 @@ func TestParseAddr(t *testing.T) {
 
 	}
 }
+
+func TestParseSocketTable(t *testing.T) {
+	table := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000081 00:00000000 00000000  1000        0 41001 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 0100007F:C350 01 00000000:00000000 02:000A7D2E 00000000  1000        0 41002 1 0000000000000000 20 4 30 10 -1
+   2: 0100007F:1F90 0100007F:C351 08 00000000:00200000 00:00000000 00000000  1000        0 41003 1 0000000000000000 20 4 30 10 -1
+   3: 0100007F:1F90 0100007F:C352 01 00000400:00000000
+`
+	sockets := make(map[string]model.Socket)
+	parseSocketTable(strings.NewReader(table), "TCP", false, sockets)
+
+	if len(sockets) != 3 {
+		t.Fatalf("got %d sockets, want 3 (short line skipped)", len(sockets))
+	}
+	want := map[string]model.Socket{
+		"41001": {Inode: "41001", Address: "0.0.0.0", Port: 8080, State: "LISTEN", Protocol: "TCP", RxQueue: 0x81},
+		"41002": {Inode: "41002", Address: "127.0.0.1", Port: 8080, State: "ESTABLISHED", Protocol: "TCP", RemoteAddress: "127.0.0.1", RemotePort: 50000},
+		"41003": {Inode: "41003", Address: "127.0.0.1", Port: 8080, State: "CLOSE_WAIT", Protocol: "TCP", RemoteAddress: "127.0.0.1", RemotePort: 50001, RxQueue: 2 << 20},
+	}
+	for inode, w := range want {
+		if got := sockets[inode]; got != w {
+			t.Errorf("socket %s = %+v, want %+v", inode, got, w)
+		}
+	}
+}
+
+func TestSortConnections(t *testing.T) {
+	conns := []model.Socket{
+		{Port: 9000, State: "ESTABLISHED", RemoteAddress: "10.0.0.2", RemotePort: 1},
+		{Port: 8080, State: "ESTABLISHED", RemoteAddress: "10.0.0.1", RemotePort: 2},
+		{Port: 9000, State: "LISTEN"},
+		{Port: 8080, State: "ESTABLISHED", RemoteAddress: "10.0.0.1", RemotePort: 1},
+	}
+	sortConnections(conns)
+	var got []string
+	for _, c := range conns {
+		got = append(got, fmt.Sprintf("%s %d %s:%d", c.State, c.Port, c.RemoteAddress, c.RemotePort))
+	}
+	want := []string{
+		"LISTEN 9000 :0",
+		"ESTABLISHED 8080 10.0.0.1:1",
+		"ESTABLISHED 8080 10.0.0.1:2",
+		"ESTABLISHED 9000 10.0.0.2:1",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package proc
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NspawnMachine returns the machine name from the cgroup of a
+// systemd-nspawn container, or "". Machines register either as a
+// systemd-machined scope or, when started with machinectl start, as an
+// instance of systemd-nspawn@.service:
+//
+//	/machine.slice/machine-<name>.scope
+//	/machine.slice/systemd-nspawn@<name>.service
+//
+// Names are systemd-escaped, so "machine-web\x2d1.scope" is machine
+// "web-1". libvirt VMs and LXC domains register scopes in the same slice
+// and are skipped.
+func NspawnMachine(cgroup string) string {
+	for _, form := range []struct{ prefix, suffix string }{
+		{"machine.slice/machine-", ".scope"},
+		{"machine.slice/systemd-nspawn@", ".service"},
+	} {
+		idx := strings.Index(cgroup, form.prefix)
+		if idx == -1 {
+			continue
+		}
+		rest := cgroup[idx+len(form.prefix):]
+		end := strings.Index(rest, form.suffix)
+		if end == -1 {
+			continue
+		}
+		name := unescapeSystemdName(rest[:end])
+		if strings.HasPrefix(name, "qemu-") || strings.HasPrefix(name, "lxc-") {
+			return ""
+		}
+		return name
+	}
+	return ""
+}
+
+// unescapeSystemdName reverses systemd's \xNN unit name escaping.
+func unescapeSystemdName(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) && s[i+1] == 'x' {
+			if v, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
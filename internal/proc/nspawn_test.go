@@ -0,0 +1,26 @@
+package proc
+
+import "testing"
+
+func TestNspawnMachine(t *testing.T) {
+	tests := []struct {
+		name   string
+		cgroup string
+		want   string
+	}{
+		{"machined scope", "0::/machine.slice/machine-debian.scope/payload/system.slice/sshd.service\n", "debian"},
+		{"escaped name", "0::/machine.slice/machine-web\\x2d1.scope/payload\n", "web-1"},
+		{"machinectl start", "0::/machine.slice/systemd-nspawn@fedora.service/payload/system.slice/httpd.service\n", "fedora"},
+		{"escaped instance", "0::/machine.slice/systemd-nspawn@build\\x2dhost.service/payload\n", "build-host"},
+		{"libvirt vm", "0::/machine.slice/machine-qemu\\x2d1\\x2dwin11.scope/libvirt/emulator\n", ""},
+		{"libvirt lxc", "0::/machine.slice/machine-lxc\\x2d4211\\x2dweb.scope\n", ""},
+		{"service", "0::/system.slice/sshd.service\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NspawnMachine(tt.cgroup); got != tt.want {
+				t.Fatalf("NspawnMachine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package proc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// PacctPaths are where distributions write BSD process accounting records
+// (Debian/Ubuntu first, then RHEL/Fedora). Overridable for tests.
+var PacctPaths = []string{"/var/log/account/pacct", "/var/account/pacct"}
+
+const (
+	acctV3Size    = 64
+	acctVersion   = 3
+	acctByteOrder = 0x80 // set in ac_version on big-endian writers
+	acctHZ        = 100  // AHZ: units of ac_etime
+	acctCommLen   = 16
+	// acctSlack absorbs the one-second btime resolution and the drift
+	// between /proc start times (derived from boot time) and wall clock
+	acctSlack = 2 * time.Second
+)
+
+// AcctRecord is one exited process from an acct_v3 accounting file.
+type AcctRecord struct {
+	PID      int
+	PPID     int
+	UID      int
+	ExitCode int
+	Command  string
+	Start    time.Time
+	Exit     time.Time
+}
+
+// parseAcctV3 decodes a 64-byte struct acct_v3 record. Records of other
+// versions are rejected.
+func parseAcctV3(b []byte) (AcctRecord, error) {
+	if len(b) < acctV3Size {
+		return AcctRecord{}, io.ErrUnexpectedEOF
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	version := b[1]
+	if version&acctByteOrder != 0 {
+		order = binary.BigEndian
+		version &^= acctByteOrder
+	}
+	if version != acctVersion {
+		return AcctRecord{}, errors.New("not an acct v3 record (version " + strconv.Itoa(int(version)) + ")")
+	}
+
+	btime := order.Uint32(b[24:28])
+	etime := math.Float32frombits(order.Uint32(b[28:32]))
+	comm := b[48 : 48+acctCommLen]
+	if i := strings.IndexByte(string(comm), 0); i >= 0 {
+		comm = comm[:i]
+	}
+
+	start := time.Unix(int64(btime), 0)
+	return AcctRecord{
+		ExitCode: int(order.Uint32(b[4:8])),
+		UID:      int(order.Uint32(b[8:12])),
+		PID:      int(order.Uint32(b[16:20])),
+		PPID:     int(order.Uint32(b[20:24])),
+		Command:  string(comm),
+		Start:    start,
+		Exit:     start.Add(time.Duration(float64(etime) / acctHZ * float64(time.Second))),
+	}, nil
+}
+
+// readAccounting returns the records of path that could be ancestors of a
+// process started at since: started no later, and exited no earlier. The
+// result is indexed by PID.
+func readAccounting(path string, since time.Time) (map[int][]AcctRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records := make(map[int][]AcctRecord)
+	r := bufio.NewReaderSize(f, 64*acctV3Size)
+	buf := make([]byte, acctV3Size)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return records, err
+		}
+		rec, err := parseAcctV3(buf)
+		if err != nil {
+			continue
+		}
+		if !aliveAt(rec, since) {
+			continue
+		}
+		records[rec.PID] = append(records[rec.PID], rec)
+	}
+	return records, nil
+}
+
+func aliveAt(rec AcctRecord, t time.Time) bool {
+	return !rec.Start.After(t.Add(acctSlack)) && !rec.Exit.Before(t.Add(-acctSlack))
+}
+
+// findAcct returns the record for pid that was alive at t, preferring the
+// most recent when a PID was reused.
+func findAcct(records map[int][]AcctRecord, pid int, t time.Time) (AcctRecord, bool) {
+	var best AcctRecord
+	found := false
+	for _, rec := range records[pid] {
+		if !aliveAt(rec, t) {
+			continue
+		}
+		if !found || rec.Start.After(best.Start) {
+			best, found = rec, true
+		}
+	}
+	return best, found
+}
+
+// reconstructExited rebuilds the exited ancestors of an adopted process.
+// The process group or session leader anchors the search: from there the
+// records' PPIDs lead up to the first live ancestor, and the leader's
+// descendants that were alive when p started lead down to its original
+// parent. It returns the exited ancestors root first, and the PID of the
+// first live ancestor (0 if the trail ends in the accounting data).
+func reconstructExited(p model.Process, records map[int][]AcctRecord, alive func(pid int, before time.Time) bool) ([]model.Process, int) {
+	var leader AcctRecord
+	found := false
+	for _, pid := range []int{p.PGID, p.SessionID} {
+		if pid <= 0 || pid == p.PID {
+			continue
+		}
+		if leader, found = findAcct(records, pid, p.StartedAt); found {
+			break
+		}
+	}
+	if !found {
+		return nil, 0
+	}
+
+	seen := map[int]bool{leader.PID: true}
+
+	// Up from the leader, nearest first
+	var up []AcctRecord
+	livePID := 0
+	for cur := leader; ; {
+		if alive(cur.PPID, cur.Start) {
+			livePID = cur.PPID
+			break
+		}
+		parent, ok := findAcct(records, cur.PPID, cur.Start)
+		if !ok || seen[parent.PID] {
+			break
+		}
+		seen[parent.PID] = true
+		up = append(up, parent)
+		cur = parent
+	}
+
+	// Down from the leader to the process that forked p
+	var down []AcctRecord
+	for cur := leader; ; {
+		child, ok := latestChild(records, cur, p.StartedAt, seen)
+		if !ok {
+			break
+		}
+		seen[child.PID] = true
+		down = append(down, child)
+		cur = child
+	}
+
+	var exited []model.Process
+	for i := len(up) - 1; i >= 0; i-- {
+		exited = append(exited, exitedProcess(up[i]))
+	}
+	exited = append(exited, exitedProcess(leader))
+	for _, rec := range down {
+		exited = append(exited, exitedProcess(rec))
+	}
+	return exited, livePID
+}
+
+// latestChild returns the most recently started child of parent that was
+// alive at t.
+func latestChild(records map[int][]AcctRecord, parent AcctRecord, t time.Time, seen map[int]bool) (AcctRecord, bool) {
+	var best AcctRecord
+	found := false
+	for _, recs := range records {
+		for _, rec := range recs {
+			if rec.PPID != parent.PID || seen[rec.PID] || rec.Start.Before(parent.Start) || !aliveAt(rec, t) {
+				continue
+			}
+			if !found || rec.Start.After(best.Start) || (rec.Start.Equal(best.Start) && rec.PID > best.PID) {
+				best, found = rec, true
+			}
+		}
+	}
+	return best, found
+}
+
+func exitedProcess(rec AcctRecord) model.Process {
+	return model.Process{
+		PID:       rec.PID,
+		PPID:      rec.PPID,
+		Command:   rec.Command,
+		User:      acctUser(rec.UID),
+		StartedAt: rec.Start,
+		Exited:    true,
+		ExitedAt:  rec.Exit,
+		Forked:    "unknown",
+	}
+}
+
+func acctUser(uid int) string {
+	if uid == 0 {
+		return "root"
+	}
+	if u, err := user.LookupId(strconv.Itoa(uid)); err == nil {
+		return u.Username
+	}
+	return strconv.Itoa(uid)
+}
+
+// ReconstructAncestry fills in exited ancestors of an adopted target from
+// process accounting. The chain is rebuilt from the first live ancestor
+// when the trail reaches one; otherwise the exited processes are placed
+// between the adopter and the target. The chain is returned unchanged when
+// accounting is off or has no matching records.
+func ReconstructAncestry(chain []model.Process) []model.Process {
+	if len(chain) < 2 {
+		return chain
+	}
+	target := chain[len(chain)-1]
+	if target.Adoption == nil {
+		return chain
+	}
+
+	for _, path := range PacctPaths {
+		records, err := readAccounting(path, target.StartedAt)
+		if err != nil || len(records) == 0 {
+			continue
+		}
+		exited, livePID := reconstructExited(target, records, liveAncestor)
+		if len(exited) == 0 {
+			continue
+		}
+		prefix := chain[:len(chain)-1]
+		if livePID > 0 {
+			if live, err := ResolveAncestry(livePID); err == nil {
+				prefix = live
+			}
+		}
+		return append(append(append([]model.Process{}, prefix...), exited...), target)
+	}
+	return chain
+}
+
+// liveAncestor reports whether pid is running and is the same process
+// that existed at before, not a later reuse of the PID.
+func liveAncestor(pid int, before time.Time) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := ReadProcess(pid)
+	if err != nil {
+		return false
+	}
+	return !p.StartedAt.After(before.Add(acctSlack))
+}
@@ -0,0 +1,101 @@
+package proc
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// testdata/pacct/nohup-v3 was recorded on Linux with accounting enabled
+// while running: bash -c 'sh -c "nohup sleep 305 >/dev/null 2>&1 &"; sleep 0.3'
+// bash (pid 11075) exec'd sleep 0.3; sh (pid 11076) forked the nohup'd
+// sleep (pid 11077), which was adopted by init.
+const (
+	fixtureSession = 11071
+	fixtureLeader  = 11075
+	fixtureTarget  = 11077
+)
+
+var fixtureStart = time.Unix(1792365038, 990_000_000)
+
+func TestReadAccounting(t *testing.T) {
+	records, err := readAccounting("testdata/pacct/nohup-v3", fixtureStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// python3, ls and bash 11012 started seconds earlier and had exited
+	if _, ok := records[11012]; ok {
+		t.Error("record that exited before the target started was kept")
+	}
+
+	rec, ok := findAcct(records, fixtureLeader, fixtureStart)
+	if !ok {
+		t.Fatalf("no record for pid %d", fixtureLeader)
+	}
+	if rec.Command != "sleep" || rec.PPID != fixtureSession || rec.UID != 0 {
+		t.Errorf("record = %+v", rec)
+	}
+	if got := rec.Exit.Sub(rec.Start); got != 300*time.Millisecond {
+		t.Errorf("elapsed = %v, want 300ms", got)
+	}
+}
+
+func TestReconstructExited(t *testing.T) {
+	records, err := readAccounting("testdata/pacct/nohup-v3", fixtureStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := model.Process{PID: fixtureTarget, PPID: 1, PGID: fixtureLeader, SessionID: fixtureSession, StartedAt: fixtureStart}
+
+	nothingAlive := func(int, time.Time) bool { return false }
+	exited, live := reconstructExited(target, records, nothingAlive)
+	if live != 0 {
+		t.Errorf("live ancestor = %d, want none", live)
+	}
+	var got []int
+	for _, p := range exited {
+		got = append(got, p.PID)
+		if !p.Exited || p.ExitedAt.IsZero() || p.User != "root" {
+			t.Errorf("exited process = %+v", p)
+		}
+	}
+	if len(got) != 2 || got[0] != fixtureLeader || got[1] != 11076 {
+		t.Errorf("exited chain = %v, want [%d 11076]", got, fixtureLeader)
+	}
+
+	sessionAlive := func(pid int, _ time.Time) bool { return pid == fixtureSession }
+	if _, live := reconstructExited(target, records, sessionAlive); live != fixtureSession {
+		t.Errorf("live ancestor = %d, want %d", live, fixtureSession)
+	}
+
+	// A process started by its own group leader has nothing to anchor on
+	if exited, _ := reconstructExited(model.Process{PID: 500, PGID: 500, StartedAt: fixtureStart}, records, nothingAlive); exited != nil {
+		t.Errorf("unexpected chain %v", exited)
+	}
+}
+
+func TestParseAcctV3(t *testing.T) {
+	b := make([]byte, acctV3Size)
+	b[1] = acctVersion | acctByteOrder
+	binary.BigEndian.PutUint32(b[16:], 4242)
+	binary.BigEndian.PutUint32(b[20:], 4000)
+	binary.BigEndian.PutUint32(b[24:], 1700000000)
+	binary.BigEndian.PutUint32(b[28:], math.Float32bits(250))
+	copy(b[48:], "deploy.sh")
+
+	rec, err := parseAcctV3(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.PID != 4242 || rec.PPID != 4000 || rec.Command != "deploy.sh" || !rec.Exit.Equal(time.Unix(1700000002, 500_000_000)) {
+		t.Errorf("big-endian record = %+v", rec)
+	}
+
+	b[1] = 2
+	if _, err := parseAcctV3(b); err == nil {
+		t.Error("acct v2 record accepted")
+	}
+}
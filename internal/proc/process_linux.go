@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/pranshuparmar/witr/pkg/model"
@@ -78,16 +79,48 @@ func ReadProcess(pid int) (model.Process, error) {
 
 	// Container detection
 	container := ""
+	var pod *model.KubernetesPod
+	var containerInfo *model.ContainerInfo
 	cgroupFile := fmt.Sprintf("/proc/%d/cgroup", pid)
 	if cgroupData, err := os.ReadFile(cgroupFile); err == nil {
 		cgroupStr := string(cgroupData)
 		var containerID string
 		switch {
+		// kubepods first: pods on docker or containerd runtimes also match those cases
+		case strings.Contains(cgroupStr, "kubepods"):
+			container = "kubernetes"
+			pod = resolveKubePod(cgroupStr)
+			if pod != nil {
+				if label := kubePodLabel(pod); label != "" {
+					container = label
+				}
+			}
+			if container == "kubernetes" {
+				if id := findLongHexID(cgroupStr); id != "" {
+					containerID = id
+					if name := resolveContainerName(containerID, "crictl"); name != "" {
+						container = "k8s: " + name
+					} else {
+						container = "k8s (" + containerID[:12] + ")"
+					}
+				}
+			}
+
+		// System containers next: their payload may itself run docker or containerd
+		case parseLXCCgroup(cgroupStr) != "":
+			containerInfo = resolveLXCContainer(parseLXCCgroup(cgroupStr))
+			container = containerInfo.Runtime + ": " + containerInfo.Name
+
+		case NspawnMachine(cgroupStr) != "":
+			containerInfo = resolveNspawnMachine(NspawnMachine(cgroupStr))
+			container = "nspawn: " + containerInfo.Name
+
 		case strings.Contains(cgroupStr, "docker"):
 			container = "docker"
 			containerID = extractContainerID(cgroupStr, "docker-", "docker/")
 			if containerID != "" {
-				if name := resolveContainerName(containerID, "docker"); name != "" {
+				containerInfo = resolveDockerContainer(containerID)
+				if name := dockerContainerLabel(containerInfo); name != "" {
 					container = name
 				} else {
 					if len(containerID) > 12 {
@@ -109,17 +142,6 @@ func ReadProcess(pid int) (model.Process, error) {
 				}
 			}
 
-		case strings.Contains(cgroupStr, "kubepods"):
-			container = "kubernetes"
-			if id := findLongHexID(cgroupStr); id != "" {
-				containerID = id
-				if name := resolveContainerName(containerID, "crictl"); name != "" {
-					container = "k8s: " + name
-				} else {
-					container = "k8s (" + containerID[:12] + ")"
-				}
-			}
-
 		case strings.Contains(cgroupStr, "containerd"):
 			container = "containerd"
 			if id := findLongHexID(cgroupStr); id != "" {
@@ -207,6 +229,10 @@ func ReadProcess(pid int) (model.Process, error) {
 
 	ppid, _ := strconv.Atoi(fields[1])
 	state := processState(fields)
+	pgid, _ := strconv.Atoi(fields[2])
+	sid, _ := strconv.Atoi(fields[3])
+	ttyNr, _ := strconv.Atoi(fields[4])
+	flags, _ := strconv.ParseUint(fields[6], 10, 64)
 	startTicks, _ := strconv.ParseInt(fields[19], 10, 64)
 
 	// Fork detection: if ppid != 1 and not systemd, likely forked; also check for vfork/fork/clone flags if possible
@@ -288,6 +314,12 @@ func ReadProcess(pid int) (model.Process, error) {
 		cmdline = strings.TrimSpace(cmd)
 	}
 
+	kernelThread := isKernelThread(pid, ppid, flags, cmdline)
+	if kernelThread {
+		// Kernel threads are created by kthreadd, not forked from userland
+		forked = "not-forked"
+	}
+
 	if comm == "docker-proxy" && container == "" {
 		container = resolveDockerProxyContainer(cmdline)
 	}
@@ -297,22 +329,89 @@ func ReadProcess(pid int) (model.Process, error) {
 		PPID:           ppid,
 		Command:        comm,
 		Cmdline:        cmdline,
+		Exe:            readExe(pid),
 		StartedAt:      startedAt,
 		User:           user,
 		WorkingDir:     cwd,
 		GitRepo:        gitRepo,
 		GitBranch:      gitBranch,
 		Container:      container,
+		Pod:            pod,
+		ContainerInfo:  containerInfo,
 		Service:        service,
 		ListeningPorts: ports,
 		BindAddresses:  addrs,
 		Health:         health,
 		Forked:         forked,
+		KernelThread:   kernelThread,
+		PGID:           pgid,
+		SessionID:      sid,
+		ControllingTTY: ttyNr != 0,
+		IgnoresHangup:  ignoresHangup(pid),
 		Env:            env,
 		ExeDeleted:     isBinaryDeleted(pid),
+		ExeReplaced:    isBinaryReplaced(pid),
 	}, nil
 }
 
+// pfKthread is the PF_KTHREAD bit of the flags field in /proc/<pid>/stat.
+const pfKthread = 0x00200000
+
+// isKernelThread trusts PF_KTHREAD when the kernel reports it, and otherwise
+// falls back to kthreadd (pid 2) or its children with no command line.
+func isKernelThread(pid, ppid int, flags uint64, cmdline string) bool {
+	if flags&pfKthread != 0 {
+		return true
+	}
+	return cmdline == "" && (pid == 2 || ppid == 2)
+}
+
+// ignoresHangup reports whether SIGHUP is in the process's ignored signal
+// mask, which nohup sets before exec.
+func ignoresHangup(pid int) bool {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return false
+	}
+	for line := range strings.Lines(string(data)) {
+		if mask, ok := strings.CutPrefix(line, "SigIgn:"); ok {
+			bits, err := strconv.ParseUint(strings.TrimSpace(mask), 16, 64)
+			return err == nil && bits&(1<<(syscall.SIGHUP-1)) != 0
+		}
+	}
+	return false
+}
+
+// readExe returns the executable path, without the " (deleted)" marker the
+// kernel appends once the file is unlinked.
+func readExe(pid int) string {
+	exePath, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(exePath, " (deleted)")
+}
+
+// isBinaryReplaced reports whether the path the process was started from now
+// holds a different file, as after a package upgrade or an in-place swap.
+// The path is resolved in the process's own root (containers).
+func isBinaryReplaced(pid int) bool {
+	exe := fmt.Sprintf("/proc/%d/exe", pid)
+	running, err := os.Stat(exe)
+	if err != nil {
+		return false
+	}
+	path, err := os.Readlink(exe)
+	if err != nil || strings.HasSuffix(path, " (deleted)") {
+		return false
+	}
+	onDisk, err := os.Stat(RootPath(pid, path))
+	if err != nil {
+		return false
+	}
+	return !os.SameFile(running, onDisk)
+}
+
 func isBinaryDeleted(pid int) bool {
 	exePath, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
 	if err != nil {
@@ -334,25 +433,14 @@ func resolveDockerProxyContainer(cmdline string) string {
 		return ""
 	}
 
-	out, err := exec.Command("docker", "network", "inspect", "bridge",
-		"--format", "{{range .Containers}}{{.Name}}:{{.IPv4Address}}{{\"\\n\"}}{{end}}").Output()
-	if err != nil {
-		return ""
+	if info := engineContainerByIP(containerIP); info != nil && info.Name != "" {
+		return "target: " + info.Name
 	}
-
-	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
-		if line == "" {
-			continue
-		}
-		colonIdx := strings.Index(line, ":")
-		if colonIdx == -1 {
-			continue
-		}
-		name := line[:colonIdx]
-		ip := strings.Split(line[colonIdx+1:], "/")[0]
-		if ip == containerIP {
-			return "target: " + name
-		}
+	if info := dockerContainerByIP(containerIP); info != nil && info.Name != "" {
+		return "target: " + info.Name
+	}
+	if name := dockerNetworkContainerByIP(containerIP); name != "" {
+		return "target: " + name
 	}
 	return ""
 }
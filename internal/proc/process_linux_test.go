@@ -0,0 +1,41 @@
+//go:build linux
+
+package proc
+
+import "testing"
+
+func TestIsKernelThread(t *testing.T) {
+	tests := []struct {
+		name    string
+		pid     int
+		ppid    int
+		flags   uint64
+		cmdline string
+		want    bool
+	}{
+		{"PF_KTHREAD set", 37, 2, 0x04208040, "", true},
+		{"kthreadd without flags", 2, 0, 0, "", true},
+		{"child of kthreadd without flags", 90, 2, 0, "", true},
+		{"userland process", 812, 1, 0x00400100, "/usr/sbin/sshd -D", false},
+		{"pid 2 in a container", 2, 1, 0x00400100, "nginx: master process", false},
+		{"zombie", 950, 812, 0x00400104, "", false},
+	}
+	for _, tt := range tests {
+		if got := isKernelThread(tt.pid, tt.ppid, tt.flags, tt.cmdline); got != tt.want {
+			t.Errorf("%s: isKernelThread = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseZombieStat(t *testing.T) {
+	zombie := []byte("4242 (worker (2)) Z 812 4242 4242 0 -1 4227148 0 0 0 0 0 0 0 0 20 0 1 0 1000 0 0 18446744073709551615 0 0 0 0 0 0 0 0 0 0 0 0 17 1 0 0 0 0 0")
+	p, ok := parseZombieStat(4242, zombie)
+	if !ok || p.PPID != 812 || p.Command != "worker (2)" || p.Health != "zombie" {
+		t.Errorf("parseZombieStat = %+v, %v", p, ok)
+	}
+
+	running := []byte("4243 (worker) S 812 4243 4243 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 1000 0 0 18446744073709551615 0 0 0 0 0 0 0 0 0 0 0 0 17 1 0 0 0 0 0")
+	if _, ok := parseZombieStat(4243, running); ok {
+		t.Error("running process reported as zombie")
+	}
+}
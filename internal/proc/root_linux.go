@@ -0,0 +1,37 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"strconv"
+)
+
+// RootPath returns path as seen by pid, through its root directory, so files
+// of containerized processes are read from their own mount namespace rather
+// than witr's.
+func RootPath(pid int, path string) string {
+	return "/proc/" + strconv.Itoa(pid) + "/root" + path
+}
+
+// SharesRoot reports whether pid has the same root directory as witr, i.e.
+// whether host paths and package databases describe its files. False when
+// the root cannot be read.
+func SharesRoot(pid int) bool {
+	theirs, err := os.Stat("/proc/" + strconv.Itoa(pid) + "/root")
+	if err != nil {
+		return false
+	}
+	ours, err := os.Stat("/proc/self/root")
+	if err != nil {
+		return false
+	}
+	return os.SameFile(theirs, ours)
+}
+
+// PIDNamespace returns the PID namespace link of pid, e.g.
+// "pid:[4026531836]", or "" when it cannot be read.
+func PIDNamespace(pid int) string {
+	link, _ := os.Readlink("/proc/" + strconv.Itoa(pid) + "/ns/pid")
+	return link
+}
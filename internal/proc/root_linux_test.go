@@ -0,0 +1,20 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRootPath(t *testing.T) {
+	if got := RootPath(42, "/usr/sbin/nginx"); got != "/proc/42/root/usr/sbin/nginx" {
+		t.Errorf("RootPath = %q", got)
+	}
+	if !SharesRoot(os.Getpid()) {
+		t.Error("SharesRoot(self) = false")
+	}
+	if SharesRoot(-1) {
+		t.Error("SharesRoot of a missing pid = true")
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package proc
+
+// RootPath returns path unchanged; per-process roots are only resolved on Linux.
+func RootPath(pid int, path string) string {
+	return path
+}
+
+// SharesRoot reports true; per-process roots are only resolved on Linux.
+func SharesRoot(pid int) bool {
+	return true
+}
+
+// PIDNamespace returns ""; namespaces are Linux-only.
+func PIDNamespace(pid int) string {
+	return ""
+}
@@ -0,0 +1,95 @@
+package proc
+
+import (
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// capNames indexes Linux capability names by bit number (linux/capability.h).
+var capNames = []string{
+	"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_DAC_READ_SEARCH", "CAP_FOWNER",
+	"CAP_FSETID", "CAP_KILL", "CAP_SETGID", "CAP_SETUID",
+	"CAP_SETPCAP", "CAP_LINUX_IMMUTABLE", "CAP_NET_BIND_SERVICE", "CAP_NET_BROADCAST",
+	"CAP_NET_ADMIN", "CAP_NET_RAW", "CAP_IPC_LOCK", "CAP_IPC_OWNER",
+	"CAP_SYS_MODULE", "CAP_SYS_RAWIO", "CAP_SYS_CHROOT", "CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT", "CAP_SYS_ADMIN", "CAP_SYS_BOOT", "CAP_SYS_NICE",
+	"CAP_SYS_RESOURCE", "CAP_SYS_TIME", "CAP_SYS_TTY_CONFIG", "CAP_MKNOD",
+	"CAP_LEASE", "CAP_AUDIT_WRITE", "CAP_AUDIT_CONTROL", "CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE", "CAP_MAC_ADMIN", "CAP_SYSLOG", "CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND", "CAP_AUDIT_READ", "CAP_PERFMON", "CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+}
+
+// decodeCaps turns a hex capability mask from /proc/<pid>/status into names.
+// Bits newer than capNames are reported as "CAP_<bit>".
+func decodeCaps(hex string) []string {
+	mask, err := strconv.ParseUint(hex, 16, 64)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, bits.OnesCount64(mask))
+	for bit := 0; mask != 0; bit++ {
+		if mask&1 == 1 {
+			if bit < len(capNames) {
+				names = append(names, capNames[bit])
+			} else {
+				names = append(names, "CAP_"+strconv.Itoa(bit))
+			}
+		}
+		mask >>= 1
+	}
+	return names
+}
+
+// ReadSecurityInfo reports the privilege posture of a process: capability
+// sets, no_new_privs, seccomp and LSM label where the platform has them,
+// plus the setuid/setgid bits of its executable.
+func ReadSecurityInfo(pid int, exe string) *model.SecurityInfo {
+	sec := readSecurity(pid)
+	if sec == nil {
+		sec = &model.SecurityInfo{}
+	}
+
+	// Paths are looked up in the process's own root, which differs from
+	// witr's for containers, when that root is readable
+	onDisk := func(path string) string { return path }
+	if _, err := os.Stat(RootPath(pid, "/")); err == nil {
+		onDisk = func(path string) string { return RootPath(pid, path) }
+	}
+
+	// Prefer the running image so a replaced file does not mislead
+	fi, err := os.Stat(fmt.Sprintf("/proc/%d/exe", pid))
+	if err != nil && exe != "" {
+		fi, err = os.Stat(onDisk(exe))
+	}
+	if err == nil {
+		sec.Setuid = fi.Mode()&os.ModeSetuid != 0
+		sec.Setgid = fi.Mode()&os.ModeSetgid != 0
+		sec.ExeMode = fi.Mode().String()
+	}
+	// Windows reports synthetic permission bits; ACLs decide writability there
+	if runtime.GOOS == "windows" {
+		return sec
+	}
+	if err == nil {
+		sec.ExeWorldWritable = fi.Mode().Perm()&0o002 != 0
+	}
+
+	// memfd and other anonymous images have no directory or mount
+	if filepath.IsAbs(exe) && !strings.HasPrefix(exe, "/memfd:") {
+		// A sticky world-writable directory (/tmp) only lets owners replace
+		// their own files
+		if di, err := os.Stat(onDisk(filepath.Dir(exe))); err == nil {
+			sec.ExeDirWorldWritable = di.Mode().Perm()&0o002 != 0 && di.Mode()&os.ModeSticky == 0
+		}
+		sec.NoexecMount = noexecMount(pid, exe)
+	}
+	return sec
+}
@@ -0,0 +1,158 @@
+//go:build linux
+
+package proc
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+var seccompModes = map[string]string{
+	"0": "disabled",
+	"1": "strict",
+	"2": "filter",
+}
+
+func readSecurity(pid int) *model.SecurityInfo {
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return nil
+	}
+	sec := parseSecurityStatus(string(status))
+	sec.LSM, sec.LSMLabel = readLSMLabel(pid)
+	if link, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/user", pid)); err == nil {
+		sec.InitUserNamespace = isInitUserNS(link)
+	}
+	return sec
+}
+
+// isInitUserNS reports whether a /proc/<pid>/ns/user link names the
+// initial user namespace, whose inode the kernel fixes at
+// PROC_USER_INIT_INO. Comparing with pid 1 would need root.
+func isInitUserNS(link string) bool {
+	return link == "user:[4026531837]"
+}
+
+// parseSecurityStatus extracts capability sets, no_new_privs and the seccomp
+// mode from /proc/<pid>/status.
+func parseSecurityStatus(status string) *model.SecurityInfo {
+	sec := &model.SecurityInfo{}
+	for _, line := range strings.Split(status, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "CapEff":
+			sec.CapEffective = decodeCaps(value)
+		case "CapPrm":
+			sec.CapPermitted = decodeCaps(value)
+		case "CapBnd":
+			sec.CapBounding = decodeCaps(value)
+		case "CapAmb":
+			sec.CapAmbient = decodeCaps(value)
+		case "NoNewPrivs":
+			sec.NoNewPrivs = value == "1"
+		case "Seccomp":
+			sec.Seccomp = seccompModes[value]
+		}
+	}
+	return sec
+}
+
+// readLSMLabel returns the active LSM and the process's label. AppArmor has
+// its own attr directory on newer kernels; the shared attr/current file is
+// SELinux when the label has the user:role:type form.
+func readLSMLabel(pid int) (string, string) {
+	if data, err := os.ReadFile(fmt.Sprintf("/proc/%d/attr/apparmor/current", pid)); err == nil {
+		if label := cleanLabel(data); label != "" {
+			return "apparmor", label
+		}
+	}
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/attr/current", pid))
+	if err != nil {
+		return "", ""
+	}
+	label := cleanLabel(data)
+	if label == "" {
+		return "", ""
+	}
+	if strings.Count(label, ":") >= 2 {
+		return "selinux", label
+	}
+	if data, err := os.ReadFile("/sys/kernel/security/lsm"); err == nil && strings.Contains(string(data), "smack") && !strings.Contains(string(data), "apparmor") {
+		return "smack", label
+	}
+	return "apparmor", label
+}
+
+func cleanLabel(data []byte) string {
+	return strings.TrimSpace(strings.TrimRight(string(data), "\x00"))
+}
+
+// noexecMount returns the mount point holding path when it is mounted
+// noexec, as seen from the process's mount namespace.
+func noexecMount(pid int, path string) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/mountinfo", pid))
+	if err != nil {
+		return ""
+	}
+	mountPoint, options := mountForPath(string(data), path)
+	for _, opt := range strings.Split(options, ",") {
+		if opt == "noexec" {
+			return mountPoint
+		}
+	}
+	return ""
+}
+
+// mountForPath finds the mount in a mountinfo table that contains path
+// (the longest matching mount point; later entries shadow earlier ones)
+// and returns its mount point and per-mount options.
+func mountForPath(mountinfo, path string) (string, string) {
+	best, bestOpts := "", ""
+	for _, line := range strings.Split(mountinfo, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+		mountPoint := unescapeMountPath(fields[4])
+		if !pathWithin(path, mountPoint) || len(mountPoint) < len(best) {
+			continue
+		}
+		best, bestOpts = mountPoint, fields[5]
+	}
+	return best, bestOpts
+}
+
+func pathWithin(path, dir string) bool {
+	if dir == "/" {
+		return strings.HasPrefix(path, "/")
+	}
+	return path == dir || strings.HasPrefix(path, dir+"/")
+}
+
+// unescapeMountPath decodes the octal escapes (\040 for space) mountinfo
+// uses in paths.
+func unescapeMountPath(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
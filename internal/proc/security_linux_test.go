@@ -0,0 +1,66 @@
+//go:build linux
+
+package proc
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsInitUserNS(t *testing.T) {
+	if !isInitUserNS("user:[4026531837]") {
+		t.Error("initial namespace not recognized")
+	}
+	if isInitUserNS("user:[4026532451]") {
+		t.Error("child namespace taken for the initial one")
+	}
+}
+
+func TestParseSecurityStatus(t *testing.T) {
+	status := `Name:	nginx
+Uid:	33	33	33	33
+CapInh:	0000000000000000
+CapPrm:	0000000000000400
+CapEff:	0000000000000400
+CapBnd:	000001ffffffffff
+CapAmb:	0000000000000400
+NoNewPrivs:	1
+Seccomp:	2
+Seccomp_filters:	1
+`
+	sec := parseSecurityStatus(status)
+	if !slices.Equal(sec.CapEffective, []string{"CAP_NET_BIND_SERVICE"}) {
+		t.Errorf("CapEffective = %v", sec.CapEffective)
+	}
+	if !slices.Equal(sec.CapAmbient, []string{"CAP_NET_BIND_SERVICE"}) {
+		t.Errorf("CapAmbient = %v", sec.CapAmbient)
+	}
+	if len(sec.CapBounding) != len(capNames) {
+		t.Errorf("CapBounding has %d entries", len(sec.CapBounding))
+	}
+	if !sec.NoNewPrivs || sec.Seccomp != "filter" {
+		t.Errorf("NoNewPrivs=%v Seccomp=%q", sec.NoNewPrivs, sec.Seccomp)
+	}
+}
+
+func TestMountForPath(t *testing.T) {
+	mountinfo := `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+30 22 0:26 / /tmp rw,nosuid,nodev,noexec shared:5 - tmpfs tmpfs rw
+31 22 0:27 / /mnt/usb\040drive rw,noexec shared:6 - vfat /dev/sdb1 rw
+32 22 0:28 / /tmpfiles rw shared:7 - tmpfs tmpfs rw
+`
+	tests := []struct {
+		path, mount, opts string
+	}{
+		{"/usr/bin/ls", "/", "rw,relatime"},
+		{"/tmp/payload", "/tmp", "rw,nosuid,nodev,noexec"},
+		{"/tmpfiles/x", "/tmpfiles", "rw"},
+		{"/mnt/usb drive/run", "/mnt/usb drive", "rw,noexec"},
+	}
+	for _, tt := range tests {
+		mount, opts := mountForPath(mountinfo, tt.path)
+		if mount != tt.mount || opts != tt.opts {
+			t.Errorf("mountForPath(%q) = %q, %q; want %q, %q", tt.path, mount, opts, tt.mount, tt.opts)
+		}
+	}
+}
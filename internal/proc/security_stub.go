@@ -0,0 +1,13 @@
+//go:build !linux
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+func readSecurity(pid int) *model.SecurityInfo {
+	return nil
+}
+
+func noexecMount(pid int, path string) string {
+	return ""
+}
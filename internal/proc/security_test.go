@@ -0,0 +1,53 @@
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestDecodeCaps(t *testing.T) {
+	tests := []struct {
+		hex  string
+		want []string
+	}{
+		{"0000000000000000", []string{}},
+		{"0000000000000400", []string{"CAP_NET_BIND_SERVICE"}},
+		{"0000000000203000", []string{"CAP_NET_ADMIN", "CAP_NET_RAW", "CAP_SYS_ADMIN"}},
+		{"0000020000000001", []string{"CAP_CHOWN", "CAP_41"}},
+		{"zz", nil},
+	}
+	for _, tt := range tests {
+		got := decodeCaps(tt.hex)
+		if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("decodeCaps(%q) = %v, want %v", tt.hex, got, tt.want)
+		}
+	}
+	if n := len(decodeCaps("000001ffffffffff")); n != len(capNames) {
+		t.Errorf("full set decoded to %d names, want %d", n, len(capNames))
+	}
+}
+
+func TestReadSecurityInfoSetuid(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no setuid bit on windows")
+	}
+	exe := filepath.Join(t.TempDir(), "helper")
+	if err := os.WriteFile(exe, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(exe, 0o755|os.ModeSetuid); err != nil {
+		t.Fatal(err)
+	}
+
+	// pid -1 has no /proc entry, so the path is used
+	sec := ReadSecurityInfo(-1, exe)
+	if !sec.Setuid || sec.Setgid {
+		t.Fatalf("Setuid=%v Setgid=%v, want setuid only", sec.Setuid, sec.Setgid)
+	}
+	if sec.ExeMode != "urwxr-xr-x" {
+		t.Errorf("ExeMode = %q", sec.ExeMode)
+	}
+}
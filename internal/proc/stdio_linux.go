@@ -0,0 +1,78 @@
+//go:build linux
+
+package proc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ReadStdioSockets returns, per PID, the TCP/UDP sockets attached to fds
+// 0-2. Ptys, pipes, files and unix sockets are not reported. Sockets are
+// looked up in each process's own network namespace; PIDs whose namespace
+// tables cannot be read (the process exited, or /proc/<pid>/net is
+// inaccessible) are returned as unchecked.
+func ReadStdioSockets(pids []int) (map[int][]model.StdioSocket, []int) {
+	if len(pids) == 0 {
+		return nil, nil
+	}
+
+	own := netNamespace("self")
+	tables := make(map[string]map[string]model.Socket)
+	result := make(map[int][]model.StdioSocket)
+	var unchecked []int
+	for _, pid := range pids {
+		ns, dir := netNamespace(strconv.Itoa(pid)), "/proc/net"
+		if ns == "" || ns == own {
+			// Unknown namespaces keep the old behaviour of witr's own tables
+			ns = own
+		} else {
+			dir = "/proc/" + strconv.Itoa(pid) + "/net"
+		}
+		sockets, ok := tables[ns]
+		if !ok {
+			if _, err := os.Stat(filepath.Join(dir, "tcp")); err == nil {
+				sockets = readSocketTables(dir)
+			}
+			tables[ns] = sockets
+		}
+		if sockets == nil {
+			unchecked = append(unchecked, pid)
+			continue
+		}
+		if found := stdioSockets(pid, sockets); len(found) > 0 {
+			result[pid] = found
+		}
+	}
+	return result, unchecked
+}
+
+// netNamespace returns the net namespace link of /proc/<pid>, e.g.
+// "net:[4026531840]", or "" when it cannot be read.
+func netNamespace(pid string) string {
+	link, _ := os.Readlink("/proc/" + pid + "/ns/net")
+	return link
+}
+
+func stdioSockets(pid int, sockets map[string]model.Socket) []model.StdioSocket {
+	var found []model.StdioSocket
+	for fd := 0; fd <= 2; fd++ {
+		link, err := os.Readlink(fmt.Sprintf("/proc/%d/fd/%d", pid, fd))
+		if err != nil || !strings.HasPrefix(link, "socket:[") {
+			continue
+		}
+		inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+		if sock, ok := sockets[inode]; ok {
+			found = append(found, model.StdioSocket{FD: fd, Socket: sock})
+		}
+	}
+	return found
+}
+
+// StdioSocketsSupported reports whether ReadStdioSockets works on this platform.
+const StdioSocketsSupported = true
@@ -0,0 +1,60 @@
+//go:build linux
+
+package proc
+
+import (
+	"net"
+	"os/exec"
+	"testing"
+)
+
+func TestReadStdioSockets(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer ln.Close()
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	server, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	f, err := client.(*net.TCPConn).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	cmd := exec.Command("sleep", "5")
+	cmd.Stdin = f
+	cmd.Stdout = f
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	socks, unchecked := ReadStdioSockets([]int{cmd.Process.Pid})
+	if len(unchecked) != 0 {
+		t.Errorf("unchecked = %v, want none", unchecked)
+	}
+	got := socks[cmd.Process.Pid]
+	if len(got) != 2 {
+		t.Fatalf("got %d stdio sockets, want 2: %+v", len(got), got)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	for i, s := range got {
+		if s.FD != i || s.Protocol != "TCP" || s.RemoteAddress != "127.0.0.1" || s.RemotePort != port {
+			t.Errorf("socket %d = %+v, want fd %d to 127.0.0.1:%d", i, s, i, port)
+		}
+	}
+}
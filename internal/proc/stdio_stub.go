@@ -0,0 +1,13 @@
+//go:build !linux
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// ReadStdioSockets is only implemented on Linux.
+func ReadStdioSockets(pids []int) (map[int][]model.StdioSocket, []int) {
+	return nil, nil
+}
+
+// StdioSocketsSupported reports whether ReadStdioSockets works on this platform.
+const StdioSocketsSupported = false
@@ -0,0 +1,217 @@
+package proc
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// supervisordConfigPaths are the locations supervisord searches when it is
+// started without -c. Overridable for tests.
+var supervisordConfigPaths = []string{"/etc/supervisord.conf", "/etc/supervisor/supervisord.conf"}
+
+// iniSection is one [section] of a supervisord config and the file that
+// defined it.
+type iniSection struct {
+	file   string
+	values map[string]string
+}
+
+// supervisorEnvSources finds the environment= lines that apply to a
+// process started by supervisord: its [program:x] section, then the
+// [supervisord] section every child inherits. supervisord identifies its
+// children by SUPERVISOR_PROCESS_NAME and SUPERVISOR_GROUP_NAME.
+func supervisorEnvSources(p model.Process, parent *model.Process) []envSource {
+	env := envMap(p.Env)
+	process, group := env["SUPERVISOR_PROCESS_NAME"], env["SUPERVISOR_GROUP_NAME"]
+	if process == "" && group == "" {
+		return nil
+	}
+
+	var supervisordEnv map[string]string
+	paths := supervisordConfigPaths
+	if parent != nil {
+		supervisordEnv = envMap(parent.Env)
+		if conf := supervisordConfigArg(parent.Cmdline); conf != "" {
+			if !filepath.IsAbs(conf) && parent.WorkingDir != "" {
+				conf = filepath.Join(parent.WorkingDir, conf)
+			}
+			paths = []string{conf}
+		}
+	}
+
+	for _, path := range paths {
+		sections := readSupervisordConfig(path)
+		if sections == nil {
+			continue
+		}
+		var sources []envSource
+		seen := make(map[string]bool)
+		for _, name := range []string{"program:" + process, "program:" + group, "supervisord"} {
+			sec, ok := sections[name]
+			if !ok || seen[name] || sec.values["environment"] == "" {
+				continue
+			}
+			seen[name] = true
+			vars := parseSupervisorEnvironment(sec.values["environment"], supervisordEnv, filepath.Dir(sec.file))
+			sources = append(sources, envSource{
+				detail: "supervisord environment= in [" + name + "] of " + sec.file,
+				vars:   vars,
+			})
+		}
+		return sources
+	}
+	return nil
+}
+
+// supervisordConfigArg returns the -c/--configuration argument of a
+// supervisord command line.
+func supervisordConfigArg(cmdline string) string {
+	args := strings.Fields(cmdline)
+	for i, arg := range args {
+		switch {
+		case (arg == "-c" || arg == "--configuration") && i+1 < len(args):
+			return args[i+1]
+		case strings.HasPrefix(arg, "--configuration="):
+			return strings.TrimPrefix(arg, "--configuration=")
+		case strings.HasPrefix(arg, "-c") && len(arg) > 2:
+			return arg[2:]
+		}
+	}
+	return ""
+}
+
+// readSupervisordConfig parses a supervisord config and the files its
+// [include] section pulls in. Sections are keyed by name; later files win.
+func readSupervisordConfig(path string) map[string]iniSection {
+	sections := parseINI(path)
+	if sections == nil {
+		return nil
+	}
+	if inc, ok := sections["include"]; ok {
+		for _, pattern := range strings.Fields(inc.values["files"]) {
+			if !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(filepath.Dir(path), pattern)
+			}
+			matches, _ := filepath.Glob(pattern)
+			for _, m := range matches {
+				for name, sec := range parseINI(m) {
+					sections[name] = sec
+				}
+			}
+		}
+	}
+	return sections
+}
+
+// parseINI reads a Python ConfigParser style file: ; and # comments,
+// key = value or key: value, and indented continuation lines.
+func parseINI(path string) map[string]iniSection {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	sections := make(map[string]iniSection)
+	var cur *iniSection
+	lastKey := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if cur != nil && lastKey != "" && (raw[0] == ' ' || raw[0] == '\t') {
+			cur.values[lastKey] += " " + line
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			sec := iniSection{file: path, values: make(map[string]string)}
+			sections[name] = sec
+			cur, lastKey = &sec, ""
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 {
+			continue
+		}
+		lastKey = strings.ToLower(strings.TrimSpace(line[:sep]))
+		value := strings.TrimSpace(line[sep+1:])
+		if i := strings.Index(value, " ;"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		cur.values[lastKey] = value
+	}
+	return sections
+}
+
+// parseSupervisorEnvironment parses KEY="val",KEY2=val2, expanding
+// %(ENV_X)s from supervisord's own environment and %(here)s to the
+// config file's directory.
+func parseSupervisorEnvironment(s string, supervisordEnv map[string]string, here string) map[string]string {
+	vars := make(map[string]string)
+	for _, item := range splitOutsideQuotes(s, ',') {
+		name, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			continue
+		}
+		value = unquote(strings.TrimSpace(value))
+		value = expandSupervisor(value, supervisordEnv, here)
+		vars[strings.TrimSpace(name)] = value
+	}
+	return vars
+}
+
+func expandSupervisor(s string, env map[string]string, here string) string {
+	for {
+		start := strings.Index(s, "%(")
+		if start < 0 {
+			return s
+		}
+		end := strings.Index(s[start:], ")s")
+		if end < 0 {
+			return s
+		}
+		key := s[start+2 : start+end]
+		repl := ""
+		switch {
+		case key == "here":
+			repl = here
+		case strings.HasPrefix(key, "ENV_"):
+			repl = env[strings.TrimPrefix(key, "ENV_")]
+		default:
+			// Leave other expansions for the ${ match rule
+			repl = "${" + key + "}"
+		}
+		s = s[:start] + repl + s[start+end+2:]
+	}
+}
+
+func splitOutsideQuotes(s string, sep rune) []string {
+	var parts []string
+	var quote rune
+	start := 0
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
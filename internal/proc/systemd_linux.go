@@ -77,3 +77,74 @@ func ResolveSystemdService(port int) (string, error) {
 
 	return "", fmt.Errorf("no systemd service found for port %d", port)
 }
+
+// GetSystemdEnvironment returns the Environment= assignments of a unit and
+// the paths of its EnvironmentFile= files, in the order systemd applies
+// them.
+func GetSystemdEnvironment(unitName string) ([]string, []string, error) {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return nil, nil, fmt.Errorf("systemctl not found")
+	}
+
+	cmd := exec.Command("systemctl", "show", "--property=Environment", "--property=EnvironmentFiles", unitName)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return nil, nil, err
+	}
+	env, files := parseSystemdEnvironment(out.String())
+	return env, files, nil
+}
+
+// parseSystemdEnvironment reads `systemctl show` output. Environment= is a
+// space-separated list where assignments holding spaces are quoted;
+// each EnvironmentFiles= line is a path followed by "(ignore_errors=...)".
+func parseSystemdEnvironment(out string) ([]string, []string) {
+	var env, files []string
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || value == "" {
+			continue
+		}
+		switch key {
+		case "Environment":
+			env = append(env, splitQuoted(value)...)
+		case "EnvironmentFiles":
+			path, _, _ := strings.Cut(value, " (")
+			files = append(files, path)
+		}
+	}
+	return env, files
+}
+
+// splitQuoted splits on spaces outside double quotes, dropping the quotes
+// and unescaping backslash sequences.
+func splitQuoted(s string) []string {
+	var fields []string
+	var cur strings.Builder
+	quoted, escaped, started := false, false, false
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped, started = true, true
+		case r == '"':
+			quoted, started = !quoted, true
+		case r == ' ' && !quoted:
+			if started {
+				fields = append(fields, cur.String())
+				cur.Reset()
+				started = false
+			}
+		default:
+			cur.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		fields = append(fields, cur.String())
+	}
+	return fields
+}
@@ -0,0 +1,27 @@
+//go:build linux
+
+package proc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSystemdEnvironment(t *testing.T) {
+	out := `Environment=LANG=C.UTF-8 "GREETING=hello world" PATH=/usr/bin
+EnvironmentFiles=/etc/default/app (ignore_errors=no)
+EnvironmentFiles=/etc/app/secrets.env (ignore_errors=yes)
+`
+	env, files := parseSystemdEnvironment(out)
+	if want := []string{"LANG=C.UTF-8", "GREETING=hello world", "PATH=/usr/bin"}; !reflect.DeepEqual(env, want) {
+		t.Errorf("env = %q, want %q", env, want)
+	}
+	if want := []string{"/etc/default/app", "/etc/app/secrets.env"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("files = %q, want %q", files, want)
+	}
+
+	env, files = parseSystemdEnvironment("Environment=\n")
+	if env != nil || files != nil {
+		t.Errorf("empty unit = %q, %q", env, files)
+	}
+}
@@ -11,3 +11,7 @@ func ResolveSystemdService(port int) (string, error) {
 func GetSystemdRestartCount(unitName string) (int, error) {
 	return 0, fmt.Errorf("systemd is only supported on Linux")
 }
+
+func GetSystemdEnvironment(unitName string) ([]string, []string, error) {
+	return nil, nil, fmt.Errorf("systemd is only supported on Linux")
+}
@@ -0,0 +1,91 @@
+package proc
+
+import (
+	"context"
+	"time"
+)
+
+type ProcEventKind int
+
+const (
+	ProcExec ProcEventKind = iota + 1
+	ProcFork
+	ProcExit
+)
+
+// ProcEvent is a process lifecycle event from WatchProcesses.
+type ProcEvent struct {
+	Kind ProcEventKind
+	PID  int
+	// Parent PID, for fork events and polled execs
+	PPID int
+	// comm read as the event arrived, if the process was still there
+	Command string
+	// Exit status, when ExitKnown (the proc connector reports it, polling
+	// cannot)
+	ExitCode  int
+	Signal    int
+	ExitKnown bool
+	Time      time.Time
+}
+
+// Capture methods reported by WatchProcesses
+const (
+	WatchConnector = "proc connector"
+	WatchPolling   = "polling"
+)
+
+// pollProcesses emits exec and exit events by diffing process snapshots.
+// Processes already running when it starts are not reported. It misses
+// anything that lives shorter than the interval and cannot see exit codes.
+func pollProcesses(ctx context.Context, interval time.Duration, events chan<- ProcEvent) {
+	defer close(events)
+
+	seen := make(map[int]string)
+	if procs, err := listProcessSnapshot(); err == nil {
+		for _, p := range procs {
+			seen[p.PID] = p.Command
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			procs, err := listProcessSnapshot()
+			if err != nil {
+				continue
+			}
+			current := make(map[int]string, len(procs))
+			for _, p := range procs {
+				current[p.PID] = p.Command
+				// A changed comm under the same PID is an exec after fork
+				if comm, ok := seen[p.PID]; !ok || comm != p.Command {
+					if !send(ctx, events, ProcEvent{Kind: ProcExec, PID: p.PID, PPID: p.PPID, Command: p.Command, Time: now}) {
+						return
+					}
+				}
+			}
+			for pid := range seen {
+				if _, ok := current[pid]; !ok {
+					if !send(ctx, events, ProcEvent{Kind: ProcExit, PID: pid, Time: now}) {
+						return
+					}
+				}
+			}
+			seen = current
+		}
+	}
+}
+
+func send(ctx context.Context, events chan<- ProcEvent, ev ProcEvent) bool {
+	select {
+	case events <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
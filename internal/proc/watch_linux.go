@@ -0,0 +1,168 @@
+//go:build linux
+
+package proc
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Proc connector constants from linux/connector.h and linux/cn_proc.h
+const (
+	netlinkConnector  = 11
+	cnIdxProc         = 1
+	cnValProc         = 1
+	procCnMcastListen = 1
+	procEventFork     = 0x00000001
+	procEventExec     = 0x00000002
+	procEventExit     = 0x80000000
+
+	nlmsgHdrLen = 16
+	cnMsgLen    = 20
+	// what, cpu, timestamp_ns precede the event data union
+	procEventHdrLen = 16
+)
+
+// WatchProcesses streams process events until ctx is done. It listens on
+// the proc connector (netlink CN_PROC), which needs CAP_NET_ADMIN, and
+// falls back to polling /proc every interval. forcePoll skips the
+// connector. The returned string names the method in use.
+func WatchProcesses(ctx context.Context, interval time.Duration, forcePoll bool) (<-chan ProcEvent, string, error) {
+	events := make(chan ProcEvent, 256)
+	if !forcePoll {
+		if fd, err := openProcConnector(); err == nil {
+			go readProcConnector(ctx, fd, events)
+			return events, WatchConnector, nil
+		}
+	}
+	go pollProcesses(ctx, interval, events)
+	return events, WatchPolling, nil
+}
+
+func openProcConnector() (int, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, netlinkConnector)
+	if err != nil {
+		return -1, err
+	}
+	addr := &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: cnIdxProc}
+	if err := syscall.Bind(fd, addr); err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+
+	// nlmsghdr + cn_msg + PROC_CN_MCAST_LISTEN
+	msg := make([]byte, nlmsgHdrLen+cnMsgLen+4)
+	le := binary.NativeEndian
+	le.PutUint32(msg[0:], uint32(len(msg)))
+	le.PutUint16(msg[4:], syscall.NLMSG_DONE)
+	le.PutUint32(msg[12:], uint32(os.Getpid()))
+	le.PutUint32(msg[16:], cnIdxProc)
+	le.PutUint32(msg[20:], cnValProc)
+	le.PutUint16(msg[32:], 4)
+	le.PutUint32(msg[36:], procCnMcastListen)
+	if err := syscall.Sendto(fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+
+	// Wake up periodically so cancellation is noticed
+	tv := syscall.NsecToTimeval((200 * time.Millisecond).Nanoseconds())
+	if err := syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+	return fd, nil
+}
+
+func readProcConnector(ctx context.Context, fd int, events chan<- ProcEvent) {
+	defer close(events)
+	defer syscall.Close(fd)
+
+	buf := make([]byte, 8192)
+	for ctx.Err() == nil {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			// Timeouts let us check ctx; ENOBUFS means events were dropped
+			if errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR) || errors.Is(err, syscall.ENOBUFS) {
+				continue
+			}
+			return
+		}
+		now := time.Now()
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			continue
+		}
+		for _, m := range msgs {
+			ev, ok := parseProcEvent(m.Data)
+			if !ok {
+				continue
+			}
+			ev.Time = now
+			if ev.Kind == ProcExec {
+				// Read comm now: short-lived processes are gone in milliseconds
+				ev.Command = readComm(ev.PID)
+			}
+			if !send(ctx, events, ev) {
+				return
+			}
+		}
+	}
+}
+
+// parseProcEvent decodes the cn_msg payload of a proc connector message.
+// Thread events are dropped: only whole processes are reported.
+func parseProcEvent(data []byte) (ProcEvent, bool) {
+	le := binary.NativeEndian
+	if len(data) < cnMsgLen+procEventHdrLen+8 {
+		return ProcEvent{}, false
+	}
+	if le.Uint32(data[0:]) != cnIdxProc || le.Uint32(data[4:]) != cnValProc {
+		return ProcEvent{}, false
+	}
+	ev := data[cnMsgLen:]
+	what := le.Uint32(ev[0:])
+	body := ev[procEventHdrLen:]
+	u32 := func(i int) int { return int(le.Uint32(body[i*4:])) }
+
+	switch what {
+	case procEventFork:
+		if len(body) < 16 || u32(2) != u32(3) {
+			return ProcEvent{}, false
+		}
+		return ProcEvent{Kind: ProcFork, PID: u32(3), PPID: u32(1)}, true
+	case procEventExec:
+		if u32(0) != u32(1) {
+			return ProcEvent{}, false
+		}
+		return ProcEvent{Kind: ProcExec, PID: u32(1)}, true
+	case procEventExit:
+		if len(body) < 16 || u32(0) != u32(1) {
+			return ProcEvent{}, false
+		}
+		// exit_code is a wait status
+		status := syscall.WaitStatus(le.Uint32(body[8:]))
+		out := ProcEvent{Kind: ProcExit, PID: u32(1), ExitKnown: true}
+		if status.Signaled() {
+			out.Signal = int(status.Signal())
+		} else {
+			out.ExitCode = status.ExitStatus()
+		}
+		return out, true
+	}
+	return ProcEvent{}, false
+}
+
+func readComm(pid int) string {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/comm")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,87 @@
+//go:build linux
+
+package proc
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// procEventMsg builds a cn_msg carrying a proc_event of kind what with the
+// given u32 body fields.
+func procEventMsg(what uint32, fields ...uint32) []byte {
+	b := make([]byte, cnMsgLen+procEventHdrLen+4*len(fields))
+	le := binary.NativeEndian
+	le.PutUint32(b[0:], cnIdxProc)
+	le.PutUint32(b[4:], cnValProc)
+	le.PutUint32(b[cnMsgLen:], what)
+	for i, f := range fields {
+		le.PutUint32(b[cnMsgLen+procEventHdrLen+4*i:], f)
+	}
+	return b
+}
+
+func TestParseProcEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		ok   bool
+		want ProcEvent
+	}{
+		{
+			name: "fork",
+			data: procEventMsg(procEventFork, 100, 100, 200, 200),
+			ok:   true,
+			want: ProcEvent{Kind: ProcFork, PID: 200, PPID: 100},
+		},
+		{
+			name: "thread creation",
+			data: procEventMsg(procEventFork, 100, 100, 201, 200),
+		},
+		{
+			name: "exec",
+			data: procEventMsg(procEventExec, 200, 200),
+			ok:   true,
+			want: ProcEvent{Kind: ProcExec, PID: 200},
+		},
+		{
+			name: "exec in thread",
+			data: procEventMsg(procEventExec, 201, 200),
+		},
+		{
+			name: "exit code",
+			data: procEventMsg(procEventExit, 200, 200, 3<<8, 0),
+			ok:   true,
+			want: ProcEvent{Kind: ProcExit, PID: 200, ExitCode: 3, ExitKnown: true},
+		},
+		{
+			name: "killed by signal",
+			data: procEventMsg(procEventExit, 200, 200, 9, 0),
+			ok:   true,
+			want: ProcEvent{Kind: ProcExit, PID: 200, Signal: 9, ExitKnown: true},
+		},
+		{
+			name: "other connector",
+			data: func() []byte {
+				b := procEventMsg(procEventExec, 200, 200)
+				b[0] = 7
+				return b
+			}(),
+		},
+		{
+			name: "truncated",
+			data: procEventMsg(procEventExec)[:cnMsgLen],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseProcEvent(tt.data)
+			if ok != tt.ok {
+				t.Fatalf("parseProcEvent ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && got != tt.want {
+				t.Errorf("parseProcEvent = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package proc
+
+import (
+	"context"
+	"time"
+)
+
+// WatchProcesses streams process events until ctx is done by polling the
+// process list every interval. The returned string names the method.
+func WatchProcesses(ctx context.Context, interval time.Duration, forcePoll bool) (<-chan ProcEvent, string, error) {
+	events := make(chan ProcEvent, 256)
+	go pollProcesses(ctx, interval, events)
+	return events, WatchPolling, nil
+}
@@ -0,0 +1,69 @@
+//go:build darwin || freebsd
+
+package proc
+
+import (
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ZombieChildren returns the defunct children of ppid, oldest first.
+func ZombieChildren(ppid int) []model.Process {
+	out, err := exec.Command("ps", "-axo", "pid=,ppid=,state=,lstart=,comm=").Output()
+	if err != nil {
+		return nil
+	}
+
+	var zombies []model.Process
+	for _, line := range strings.Split(string(out), "\n") {
+		// pid, ppid, state, lstart (5 fields), comm
+		fields := strings.Fields(line)
+		if len(fields) < 9 || !strings.HasPrefix(fields[2], "Z") {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		parent, err := strconv.Atoi(fields[1])
+		if err != nil || parent != ppid {
+			continue
+		}
+		started, _ := time.ParseInLocation("Mon Jan 2 15:04:05 2006", strings.Join(fields[3:8], " "), time.Local)
+		zombies = append(zombies, model.Process{
+			PID:       pid,
+			PPID:      parent,
+			Command:   strings.Join(fields[8:], " "),
+			StartedAt: started,
+			Health:    "zombie",
+		})
+	}
+
+	sort.SliceStable(zombies, func(i, j int) bool {
+		return zombies[i].StartedAt.Before(zombies[j].StartedAt)
+	})
+	return zombies
+}
+
+// ReadWaitState returns the scheduler state letter of pid and, when it is
+// sleeping, the kernel wait channel.
+func ReadWaitState(pid int) (state, wchan string) {
+	out, err := exec.Command("ps", "-o", "state=,wchan=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", ""
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return "", ""
+	}
+	state = fields[0][:1]
+	if len(fields) > 1 && fields[1] != "-" {
+		wchan = fields[1]
+	}
+	return state, wchan
+}
@@ -0,0 +1,95 @@
+//go:build linux
+
+package proc
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ZombieChildren returns the defunct children of ppid, oldest first.
+func ZombieChildren(ppid int) []model.Process {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+
+	var zombies []model.Process
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		if err != nil {
+			continue
+		}
+		if p, ok := parseZombieStat(pid, stat); ok && p.PPID == ppid {
+			zombies = append(zombies, p)
+		}
+	}
+
+	sortByStart(zombies)
+	return zombies
+}
+
+// parseZombieStat returns the process if its stat line is in state Z.
+func parseZombieStat(pid int, stat []byte) (model.Process, bool) {
+	raw := string(stat)
+	open := strings.Index(raw, "(")
+	close := strings.LastIndex(raw, ")")
+	if open == -1 || close == -1 || close <= open || close+2 > len(raw) {
+		return model.Process{}, false
+	}
+	fields := strings.Fields(raw[close+2:])
+	if len(fields) < 20 || processState(fields) != "Z" {
+		return model.Process{}, false
+	}
+	ppid, _ := strconv.Atoi(fields[1])
+	startTicks, _ := strconv.ParseInt(fields[19], 10, 64)
+	return model.Process{
+		PID:       pid,
+		PPID:      ppid,
+		Command:   raw[open+1 : close],
+		StartedAt: bootTime().Add(time.Duration(startTicks) * time.Second / ticksPerSecond()),
+		Health:    "zombie",
+	}, true
+}
+
+// ReadWaitState returns the scheduler state letter of pid and, when it is
+// sleeping, the kernel function it waits in.
+func ReadWaitState(pid int) (state, wchan string) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return "", ""
+	}
+	raw := string(stat)
+	close := strings.LastIndex(raw, ")")
+	if close == -1 || close+2 > len(raw) {
+		return "", ""
+	}
+	state = processState(strings.Fields(raw[close+2:]))
+
+	if data, err := os.ReadFile(fmt.Sprintf("/proc/%d/wchan", pid)); err == nil {
+		wchan = strings.TrimSpace(string(data))
+		if wchan == "0" {
+			wchan = ""
+		}
+	}
+	return state, wchan
+}
+
+func sortByStart(processes []model.Process) {
+	sort.SliceStable(processes, func(i, j int) bool {
+		if !processes[i].StartedAt.Equal(processes[j].StartedAt) {
+			return processes[i].StartedAt.Before(processes[j].StartedAt)
+		}
+		return processes[i].PID < processes[j].PID
+	})
+}
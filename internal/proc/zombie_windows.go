@@ -0,0 +1,11 @@
+//go:build windows
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// ZombieChildren returns nil: Windows has no defunct process state.
+func ZombieChildren(ppid int) []model.Process { return nil }
+
+// ReadWaitState is not available on Windows.
+func ReadWaitState(pid int) (state, wchan string) { return "", "" }
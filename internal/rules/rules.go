@@ -0,0 +1,511 @@
+// Package rules loads user-defined source detectors and warning rules from
+// rules.yaml files, so in-house supervisors and launcher wrappers can be
+// attributed without changing witr itself.
+package rules
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+	"go.yaml.in/yaml/v3"
+)
+
+// Severities accepted by warning rules, from least to most severe.
+var Severities = []string{
+	string(model.SeverityInfo),
+	string(model.SeverityLow),
+	string(model.SeverityMedium),
+	string(model.SeverityHigh),
+	string(model.SeverityCritical),
+}
+
+// SystemDir holds rules files shipped by packages or configuration management.
+const SystemDir = "/etc/witr/rules.d"
+
+// defaultDetectorConfidence ranks a custom detector above shells and
+// generic init systems but below containers and sandboxes.
+const defaultDetectorConfidence = 75
+
+// Set is the merged content of every loaded rules file.
+type Set struct {
+	Detectors []*Detector
+	Warnings  []*Warning
+	Ignore    []*Ignore
+	// RedactKeys and RedactPatterns extend the built-in secret redaction
+	RedactKeys     []string
+	RedactPatterns []*regexp.Regexp
+}
+
+// File is the on-disk layout of a rules.yaml file.
+type File struct {
+	Detectors []*Detector `yaml:"detectors"`
+	Warnings  []*Warning  `yaml:"warnings"`
+	Ignore    []*Ignore   `yaml:"ignore"`
+	Redact    *Redact     `yaml:"redact"`
+}
+
+// Match selects a process. Every populated field must match.
+type Match struct {
+	// Comm is the exact process name
+	Comm string `yaml:"comm"`
+	// Cmdline is a regular expression applied to the full command line
+	Cmdline string `yaml:"cmdline"`
+	// Cgroup is a regular expression applied to /proc/<pid>/cgroup
+	Cgroup string `yaml:"cgroup"`
+	// Env is an environment variable that must be set and non-empty
+	Env string `yaml:"env"`
+
+	cmdline *regexp.Regexp
+	cgroup  *regexp.Regexp
+}
+
+// SourceSpec is the source a detector yields. Details values are templates.
+type SourceSpec struct {
+	Type    string            `yaml:"type"`
+	Name    string            `yaml:"name"`
+	Details map[string]string `yaml:"details"`
+}
+
+// Detector attributes a process to a source when an ancestor matches.
+type Detector struct {
+	Name       string     `yaml:"name"`
+	Match      Match      `yaml:"match"`
+	Source     SourceSpec `yaml:"source"`
+	Confidence int        `yaml:"confidence"`
+
+	file    string
+	details map[string]*template.Template
+}
+
+// Warning adds a warning when the target process matches.
+type Warning struct {
+	Name string `yaml:"name"`
+	// ID defaults to RULE-<NAME>
+	ID       string `yaml:"id"`
+	Severity string `yaml:"severity"`
+	Category string `yaml:"category"`
+	Match    Match  `yaml:"match"`
+	// Message is a text/template rendered against the matched process
+	Message string `yaml:"message"`
+
+	file    string
+	message *template.Template
+}
+
+// Ignore suppresses a warning ID. Every populated key must match; an entry
+// with only an ID silences that warning everywhere.
+type Ignore struct {
+	ID string `yaml:"id"`
+	// Source is a source name or type, e.g. "nginx.service" or "docker"
+	Source string `yaml:"source"`
+	// Unit is a systemd unit or service name
+	Unit string `yaml:"unit"`
+	// Exe is an executable path or glob
+	Exe string `yaml:"exe"`
+}
+
+// Redact adds secrets to mask in output on top of the built-in rules.
+type Redact struct {
+	// Keys are environment variable name globs, matched case-insensitively,
+	// e.g. "STRIPE_*"
+	Keys []string `yaml:"keys"`
+	// Patterns are regular expressions masked wherever they appear in
+	// command lines, values and evidence. When a pattern has a capture
+	// group, only the first group is masked.
+	Patterns []string `yaml:"patterns"`
+
+	patterns []*regexp.Regexp
+}
+
+// DefaultPaths returns the rules files witr reads, in load order:
+// /etc/witr/rules.d/*.yaml sorted by name, then the user's rules.yaml.
+func DefaultPaths() []string {
+	var paths []string
+	if matches, err := filepath.Glob(filepath.Join(SystemDir, "*.yaml")); err == nil {
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+	if dir := userConfigDir(); dir != "" {
+		paths = append(paths, filepath.Join(dir, "witr", "rules.yaml"))
+	}
+	return paths
+}
+
+// userConfigDir follows XDG on every platform so the documented
+// ~/.config/witr location works on macOS too.
+func userConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config")
+}
+
+// Load reads and validates the given files. Missing files are skipped.
+// Invalid rules are left out of the returned set and reported in the error.
+func Load(paths []string) (*Set, error) {
+	set := &Set{}
+	var errs []error
+	for _, path := range paths {
+		f, err := LoadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if f != nil {
+			set.Detectors = append(set.Detectors, f.Detectors...)
+			set.Warnings = append(set.Warnings, f.Warnings...)
+			set.Ignore = append(set.Ignore, f.Ignore...)
+			if f.Redact != nil {
+				set.RedactKeys = append(set.RedactKeys, f.Redact.Keys...)
+				set.RedactPatterns = append(set.RedactPatterns, f.Redact.patterns...)
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return set, errors.Join(errs...)
+}
+
+// LoadFile parses a single rules file. The returned File holds only the
+// rules that passed validation; every problem found is joined into err.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw File
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&raw); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	f := &File{}
+	var errs []error
+	for i, d := range raw.Detectors {
+		d.file = path
+		if err := d.compile(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: detector %s: %w", path, ruleName(d.Name, i), err))
+			continue
+		}
+		f.Detectors = append(f.Detectors, d)
+	}
+	for i, w := range raw.Warnings {
+		w.file = path
+		if err := w.compile(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: warning %s: %w", path, ruleName(w.Name, i), err))
+			continue
+		}
+		f.Warnings = append(f.Warnings, w)
+	}
+	for i, ig := range raw.Ignore {
+		if err := ig.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: ignore #%d: %w", path, i+1, err))
+			continue
+		}
+		f.Ignore = append(f.Ignore, ig)
+	}
+	if raw.Redact != nil {
+		// Invalid entries are dropped one by one so the rest still mask
+		if err := raw.Redact.compile(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: redact: %w", path, err))
+		}
+		f.Redact = raw.Redact
+	}
+	return f, errors.Join(errs...)
+}
+
+func ruleName(name string, i int) string {
+	if name != "" {
+		return fmt.Sprintf("%q", name)
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
+
+func (m *Match) compile() error {
+	if m.Comm == "" && m.Cmdline == "" && m.Cgroup == "" && m.Env == "" {
+		return errors.New("match needs at least one of comm, cmdline, cgroup or env")
+	}
+	var err error
+	if m.Cmdline != "" {
+		if m.cmdline, err = regexp.Compile(m.Cmdline); err != nil {
+			return fmt.Errorf("cmdline: %w", err)
+		}
+	}
+	if m.Cgroup != "" {
+		if m.cgroup, err = regexp.Compile(m.Cgroup); err != nil {
+			return fmt.Errorf("cgroup: %w", err)
+		}
+	}
+	return nil
+}
+
+func (d *Detector) compile() error {
+	if d.Name == "" {
+		return errors.New("name is required")
+	}
+	if err := d.Match.compile(); err != nil {
+		return err
+	}
+	if !knownSourceType(d.Source.Type) {
+		return fmt.Errorf("unknown source type %q", d.Source.Type)
+	}
+	if d.Source.Name == "" {
+		return errors.New("source name is required")
+	}
+	if d.Confidence < 0 || d.Confidence > 100 {
+		return fmt.Errorf("confidence %d is outside 0-100", d.Confidence)
+	}
+	if d.Confidence == 0 {
+		d.Confidence = defaultDetectorConfidence
+	}
+	d.details = make(map[string]*template.Template, len(d.Source.Details))
+	for key, text := range d.Source.Details {
+		tmpl, err := template.New(key).Option("missingkey=zero").Parse(text)
+		if err == nil {
+			err = d.Match.tryTemplate(tmpl)
+		}
+		if err != nil {
+			return fmt.Errorf("details.%s: %w", key, err)
+		}
+		d.details[key] = tmpl
+	}
+	return nil
+}
+
+func (w *Warning) compile() error {
+	if w.Name == "" {
+		return errors.New("name is required")
+	}
+	if err := w.Match.compile(); err != nil {
+		return err
+	}
+	if w.ID == "" {
+		w.ID = "RULE-" + strings.ToUpper(nonIDChars.ReplaceAllString(w.Name, "-"))
+	}
+	if w.Category == "" {
+		w.Category = model.CategoryCustom
+	}
+	if w.Severity == "" {
+		w.Severity = string(model.SeverityMedium)
+	}
+	if !slices.Contains(Severities, w.Severity) {
+		return fmt.Errorf("unknown severity %q (want one of %s)", w.Severity, strings.Join(Severities, ", "))
+	}
+	if w.Message == "" {
+		return errors.New("message is required")
+	}
+	tmpl, err := template.New(w.Name).Option("missingkey=zero").Parse(w.Message)
+	if err == nil {
+		err = w.Match.tryTemplate(tmpl)
+	}
+	if err != nil {
+		return fmt.Errorf("message: %w", err)
+	}
+	w.message = tmpl
+	return nil
+}
+
+// tryTemplate executes tmpl against an empty process. Parsing does not
+// check field names, and a template that fails at run time would render
+// nothing and silently drop its warning or detail.
+func (m *Match) tryTemplate(tmpl *template.Template) error {
+	sub := []string{""}
+	if m.cmdline != nil {
+		sub = make([]string, m.cmdline.NumSubexp()+1)
+	}
+	return tmpl.Execute(io.Discard, templateData{Match: sub})
+}
+
+var nonIDChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+func (ig *Ignore) validate() error {
+	if ig.ID == "" {
+		return errors.New("id is required")
+	}
+	if ig.Exe != "" {
+		if _, err := filepath.Match(ig.Exe, ""); err != nil {
+			return fmt.Errorf("exe: %w", err)
+		}
+	}
+	return nil
+}
+
+// compile keeps only the valid keys and patterns and reports the others.
+func (r *Redact) compile() error {
+	var errs []error
+	var keys []string
+	for _, key := range r.Keys {
+		if _, err := filepath.Match(key, ""); err != nil || key == "" {
+			errs = append(errs, fmt.Errorf("key %q is not a valid glob", key))
+			continue
+		}
+		keys = append(keys, key)
+	}
+	r.Keys = keys
+
+	var patterns []string
+	r.patterns = nil
+	for _, pattern := range r.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pattern %q: %w", pattern, err))
+			continue
+		}
+		patterns = append(patterns, pattern)
+		r.patterns = append(r.patterns, re)
+	}
+	r.Patterns = patterns
+	return errors.Join(errs...)
+}
+
+// Count is the number of keys and patterns in the section.
+func (r *Redact) Count() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.Keys) + len(r.Patterns)
+}
+
+// Matches reports whether the entry silences warning id for a process
+// with the given source, unit and executable.
+func (ig *Ignore) Matches(id string, src model.Source, unit, exe string) bool {
+	if ig.ID != id {
+		return false
+	}
+	if ig.Source != "" && ig.Source != src.Name && ig.Source != string(src.Type) {
+		return false
+	}
+	if ig.Unit != "" && ig.Unit != unit && ig.Unit+".service" != unit {
+		return false
+	}
+	if ig.Exe != "" {
+		if ok, _ := filepath.Match(ig.Exe, exe); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func knownSourceType(t string) bool {
+	switch model.SourceType(t) {
+	case model.SourceContainer, model.SourceSystemd, model.SourceLaunchd,
+		model.SourceBsdRc, model.SourceSupervisor, model.SourceCron,
+		model.SourceShell, model.SourceMultiplexer, model.SourceSandbox,
+		model.SourceCI, model.SourceWindowsService, model.SourceInit,
+		model.SourceKernel:
+		return true
+	}
+	return false
+}
+
+// NeedsCgroup reports whether matching requires the process's cgroup, so
+// callers only read /proc/<pid>/cgroup when a rule asks for it.
+func (m *Match) NeedsCgroup() bool {
+	return m.cgroup != nil
+}
+
+// Matches reports whether p matches. The cmdline submatches are returned
+// for use in templates as .Match.
+func (m *Match) Matches(p model.Process, cgroup string) ([]string, bool) {
+	if m.Comm != "" && p.Command != m.Comm {
+		return nil, false
+	}
+	if m.Env != "" && getenv(p.Env, m.Env) == "" {
+		return nil, false
+	}
+	if m.cgroup != nil && !m.cgroup.MatchString(cgroup) {
+		return nil, false
+	}
+	if m.cmdline == nil {
+		return nil, true
+	}
+	sub := m.cmdline.FindStringSubmatch(p.Cmdline)
+	if sub == nil {
+		return nil, false
+	}
+	return sub, true
+}
+
+// Evidence describes why the detector fired for p.
+func (d *Detector) Evidence(p model.Process) string {
+	return fmt.Sprintf("pid %d matches rule %q from %s", p.PID, d.Name, d.file)
+}
+
+// BuildSource builds the source for the matched process.
+func (d *Detector) BuildSource(p model.Process, sub []string) model.Source {
+	src := model.Source{
+		Type:       model.SourceType(d.Source.Type),
+		Name:       d.Source.Name,
+		Confidence: d.Confidence,
+		Evidence:   []string{d.Evidence(p)},
+	}
+	if len(d.details) > 0 {
+		src.Details = make(map[string]string, len(d.details))
+		for key, tmpl := range d.details {
+			if val := render(tmpl, p, sub); val != "" {
+				src.Details[key] = val
+			}
+		}
+	}
+	return src
+}
+
+// Render renders the warning message for the matched process.
+func (w *Warning) Render(p model.Process, sub []string) string {
+	return render(w.message, p, sub)
+}
+
+// Build renders the warning for the matched process.
+func (w *Warning) Build(p model.Process, sub []string) model.Warning {
+	return model.Warning{
+		ID:       w.ID,
+		Severity: model.Severity(w.Severity),
+		Category: w.Category,
+		Message:  w.Render(p, sub),
+		Evidence: []string{fmt.Sprintf("pid %d matches rule %q from %s", p.PID, w.Name, w.file)},
+	}
+}
+
+// templateData is what detail and message templates see: the process
+// fields plus .Match (cmdline submatches) and .Getenv.
+type templateData struct {
+	model.Process
+	Match []string
+}
+
+func (d templateData) Getenv(key string) string {
+	return getenv(d.Env, key)
+}
+
+func render(tmpl *template.Template, p model.Process, sub []string) string {
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, templateData{Process: p, Match: sub}); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+func getenv(env []string, key string) string {
+	for _, entry := range env {
+		if k, v, ok := strings.Cut(entry, "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
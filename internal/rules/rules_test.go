@@ -0,0 +1,195 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func writeRules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileDetector(t *testing.T) {
+	path := writeRules(t, `
+detectors:
+  - name: acme-procman
+    match:
+      comm: procmand
+      cmdline: '--job=(\S+)'
+    source:
+      type: supervisor
+      name: procman
+      details:
+        job: '{{index .Match 1}}'
+        owner: '{{.Getenv "PROCMAN_OWNER"}}'
+`)
+	f, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if len(f.Detectors) != 1 {
+		t.Fatalf("got %d detectors, want 1", len(f.Detectors))
+	}
+	d := f.Detectors[0]
+
+	p := model.Process{PID: 42, Command: "procmand", Cmdline: "procmand --job=billing", Env: []string{"PROCMAN_OWNER=ops"}}
+	sub, ok := d.Match.Matches(p, "")
+	if !ok {
+		t.Fatal("expected match")
+	}
+	src := d.BuildSource(p, sub)
+	if src.Type != model.SourceSupervisor || src.Name != "procman" {
+		t.Errorf("source = %s/%s", src.Type, src.Name)
+	}
+	if src.Details["job"] != "billing" || src.Details["owner"] != "ops" {
+		t.Errorf("details = %v", src.Details)
+	}
+	if src.Confidence != defaultDetectorConfidence {
+		t.Errorf("confidence = %d, want %d", src.Confidence, defaultDetectorConfidence)
+	}
+	if len(src.Evidence) != 1 || !strings.Contains(src.Evidence[0], `"acme-procman"`) {
+		t.Errorf("evidence = %v", src.Evidence)
+	}
+
+	if _, ok := d.Match.Matches(model.Process{Command: "procmand", Cmdline: "procmand"}, ""); ok {
+		t.Error("cmdline regex should be required")
+	}
+}
+
+func TestMatchCgroupAndEnv(t *testing.T) {
+	m := Match{Cgroup: `acme-.*\.scope`, Env: "ACME_TASK"}
+	if err := m.compile(); err != nil {
+		t.Fatal(err)
+	}
+	if !m.NeedsCgroup() {
+		t.Error("NeedsCgroup = false")
+	}
+	p := model.Process{Env: []string{"ACME_TASK=7"}}
+	if _, ok := m.Matches(p, "0::/system.slice/acme-7.scope\n"); !ok {
+		t.Error("expected match")
+	}
+	if _, ok := m.Matches(model.Process{Env: []string{"ACME_TASK="}}, "0::/system.slice/acme-7.scope\n"); ok {
+		t.Error("empty env value should not match")
+	}
+	if _, ok := m.Matches(p, "0::/user.slice\n"); ok {
+		t.Error("cgroup mismatch should not match")
+	}
+}
+
+func TestLoadFileWarning(t *testing.T) {
+	path := writeRules(t, `
+warnings:
+  - name: acme-debug
+    match: {env: ACME_DEBUG}
+    message: "{{.Command}} has the debug port enabled"
+`)
+	f, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	w := f.Warnings[0]
+	if w.Severity != "medium" || w.ID != "RULE-ACME-DEBUG" || w.Category != model.CategoryCustom {
+		t.Errorf("defaults = %q %q %q", w.Severity, w.ID, w.Category)
+	}
+	p := model.Process{Command: "api", Env: []string{"ACME_DEBUG=1"}}
+	sub, ok := w.Match.Matches(p, "")
+	if !ok {
+		t.Fatal("expected match")
+	}
+	if got := w.Render(p, sub); got != "api has the debug port enabled" {
+		t.Errorf("Render = %q", got)
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown field", "detectors:\n  - name: x\n    matches: {comm: x}\n", "field matches not found"},
+		{"empty match", "detectors:\n  - name: x\n    source: {type: supervisor, name: x}\n", "at least one of"},
+		{"bad regex", "detectors:\n  - name: x\n    match: {cmdline: '('}\n    source: {type: supervisor, name: x}\n", "cmdline:"},
+		{"bad type", "detectors:\n  - name: x\n    match: {comm: x}\n    source: {type: daemon, name: x}\n", `unknown source type "daemon"`},
+		{"bad confidence", "detectors:\n  - name: x\n    match: {comm: x}\n    source: {type: shell, name: x}\n    confidence: 120\n", "outside 0-100"},
+		{"bad severity", "warnings:\n  - name: w\n    severity: extreme\n    match: {comm: x}\n    message: m\n", `unknown severity "extreme"`},
+		{"bad template", "warnings:\n  - name: w\n    match: {comm: x}\n    message: '{{.Command'\n", "message:"},
+		{"unknown template field", "warnings:\n  - name: w\n    match: {comm: x}\n    message: '{{.Comand}} runs'\n", "message:"},
+		{"unknown detail field", "detectors:\n  - name: x\n    match: {comm: x}\n    source: {type: supervisor, name: x, details: {app: '{{.Nope}}'}}\n", "details.app:"},
+		{"match group out of range", "warnings:\n  - name: w\n    match: {cmdline: 'app (\\w+)'}\n    message: '{{index .Match 2}}'\n", "message:"},
+		{"ignore without id", "ignore:\n  - exe: /usr/sbin/sshd\n", "ignore #1: id is required"},
+		{"bad exe glob", "ignore:\n  - id: WITR-ROOT\n    exe: '/usr/[bin'\n", "exe:"},
+		{"bad redact pattern", "redact:\n  patterns: ['sk_(']\n", `redact: pattern "sk_("`},
+		{"bad redact key", "redact:\n  keys: ['[STRIPE']\n", `key "[STRIPE" is not a valid glob`},
+		{"unnamed", "warnings:\n  - match: {comm: x}\n    message: m\n", "warning #1: name is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFile(writeRules(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFileTemplatesRunAgainstMatchGroups(t *testing.T) {
+	f, err := LoadFile(writeRules(t, "warnings:\n  - name: w\n    match: {cmdline: 'app --env (\\w+)'}\n    message: '{{.Command}} in {{index .Match 1}} ({{.Getenv \"HOME\"}})'\n"))
+	if err != nil || len(f.Warnings) != 1 {
+		t.Fatalf("LoadFile: %v, %d warnings", err, len(f.Warnings))
+	}
+}
+
+func TestLoadSkipsMissingAndKeepsValidRules(t *testing.T) {
+	good := writeRules(t, "detectors:\n  - name: a\n    match: {comm: a}\n    source: {type: supervisor, name: a}\n  - name: b\n    match: {comm: b}\n    source: {type: nope, name: b}\n")
+	set, err := Load([]string{filepath.Join(t.TempDir(), "missing.yaml"), good})
+	if err == nil {
+		t.Fatal("expected an error for the invalid detector")
+	}
+	if len(set.Detectors) != 1 || set.Detectors[0].Name != "a" {
+		t.Fatalf("detectors = %v", set.Detectors)
+	}
+}
+
+func TestLoadRedact(t *testing.T) {
+	a := writeRules(t, "redact:\n  keys: ['STRIPE_*']\n  patterns: ['sk_live_[0-9a-zA-Z]+']\n")
+	b := writeRules(t, "redact:\n  keys: ['*_DSN']\n")
+	set, err := Load([]string{a, b})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if strings.Join(set.RedactKeys, ",") != "STRIPE_*,*_DSN" {
+		t.Errorf("RedactKeys = %v", set.RedactKeys)
+	}
+	if len(set.RedactPatterns) != 1 || !set.RedactPatterns[0].MatchString("key sk_live_abc123") {
+		t.Errorf("RedactPatterns = %v", set.RedactPatterns)
+	}
+}
+
+func TestLoadRedactKeepsValidEntries(t *testing.T) {
+	path := writeRules(t, "redact:\n  keys: ['STRIPE_*', '[']\n  patterns: ['sk_live_[0-9a-z]+', '(unclosed']\n")
+	set, err := Load([]string{path})
+	if err == nil || !strings.Contains(err.Error(), `key "["`) || !strings.Contains(err.Error(), `pattern "(unclosed"`) {
+		t.Errorf("err = %v, want both invalid entries reported", err)
+	}
+	if strings.Join(set.RedactKeys, ",") != "STRIPE_*" {
+		t.Errorf("RedactKeys = %v", set.RedactKeys)
+	}
+	if len(set.RedactPatterns) != 1 || !set.RedactPatterns[0].MatchString("sk_live_abc") {
+		t.Errorf("RedactPatterns = %v", set.RedactPatterns)
+	}
+
+	f, _ := LoadFile(path)
+	if f.Redact.Count() != 2 {
+		t.Errorf("Count = %d, want the 2 valid entries", f.Redact.Count())
+	}
+}
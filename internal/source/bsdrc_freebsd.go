@@ -66,6 +66,8 @@ func detectBsdRc(ancestry []model.Process) *model.Source {
 				src.UnitFile = path
 				src.Description = readRcDescription(path)
 			}
+			src.Confidence = 85
+			src.Evidence = []string{"pid " + itoa(p.PID) + " is recorded in the pidfile of rc service " + p.Service}
 
 			return src
 		}
@@ -95,9 +97,13 @@ func detectBsdRc(ancestry []model.Process) *model.Source {
 				Name: name,
 			}
 
+			src.Confidence = 40
+			src.Evidence = []string{"target is a direct child of init with no shell in its ancestry"}
 			if path != "" {
 				src.UnitFile = path
 				src.Description = readRcDescription(path)
+				src.Confidence = 70
+				src.Evidence = append(src.Evidence, "rc script "+path+" exists")
 			}
 
 			return src
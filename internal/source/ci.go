@@ -0,0 +1,197 @@
+package source
+
+import (
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ciProvider describes how a CI system marks its jobs and runs its agent.
+type ciProvider struct {
+	name     string
+	marker   string // env var set in every job
+	isRunner func(p model.Process) bool
+	details  func(env []string) map[string]string
+}
+
+var ciProviders = []ciProvider{
+	{
+		name:   "github-actions",
+		marker: "GITHUB_RUN_ID",
+		isRunner: func(p model.Process) bool {
+			return p.Command == "Runner.Worker" || strings.Contains(p.Cmdline, "Runner.Worker")
+		},
+		details: func(env []string) map[string]string {
+			repo := envValue(env, "GITHUB_REPOSITORY")
+			run := ""
+			if server := envValue(env, "GITHUB_SERVER_URL"); server != "" && repo != "" {
+				run = server + "/" + repo + "/actions/runs/" + envValue(env, "GITHUB_RUN_ID")
+			}
+			return map[string]string{
+				"repository": repo,
+				"workflow":   envValue(env, "GITHUB_WORKFLOW"),
+				"job":        envValue(env, "GITHUB_JOB"),
+				"run":        run,
+			}
+		},
+	},
+	{
+		name:   "gitlab-ci",
+		marker: "CI_JOB_ID",
+		isRunner: func(p model.Process) bool {
+			return p.Command == "gitlab-runner" || p.Command == "gitlab-ci-multi"
+		},
+		details: func(env []string) map[string]string {
+			workflow := ""
+			if id := envValue(env, "CI_PIPELINE_ID"); id != "" {
+				workflow = "pipeline " + id
+			}
+			return map[string]string{
+				"repository": envValue(env, "CI_PROJECT_PATH"),
+				"workflow":   workflow,
+				"job":        envValue(env, "CI_JOB_NAME"),
+				"run":        envValue(env, "CI_JOB_URL"),
+			}
+		},
+	},
+	{
+		name:   "jenkins",
+		marker: "BUILD_URL",
+		isRunner: func(p model.Process) bool {
+			if p.Command == "jenkins" {
+				return true
+			}
+			if p.Command != "java" {
+				return false
+			}
+			for _, jar := range []string{"agent.jar", "remoting.jar", "slave.jar", "jenkins.war"} {
+				if strings.Contains(p.Cmdline, jar) {
+					return true
+				}
+			}
+			return false
+		},
+		details: func(env []string) map[string]string {
+			job := envValue(env, "JOB_NAME")
+			if n := envValue(env, "BUILD_NUMBER"); job != "" && n != "" {
+				job += " #" + n
+			}
+			return map[string]string{
+				"repository": envValue(env, "GIT_URL"),
+				"job":        job,
+				"run":        envValue(env, "BUILD_URL"),
+			}
+		},
+	},
+	{
+		name:   "buildkite",
+		marker: "BUILDKITE_JOB_ID",
+		isRunner: func(p model.Process) bool {
+			return p.Command == "buildkite-agent"
+		},
+		details: func(env []string) map[string]string {
+			run := envValue(env, "BUILDKITE_BUILD_URL")
+			if run != "" {
+				run += "#" + envValue(env, "BUILDKITE_JOB_ID")
+			}
+			return map[string]string{
+				"repository": envValue(env, "BUILDKITE_REPO"),
+				"workflow":   envValue(env, "BUILDKITE_PIPELINE_SLUG"),
+				"job":        envValue(env, "BUILDKITE_LABEL"),
+				"run":        run,
+			}
+		},
+	},
+}
+
+// ciJob is a CI job found through the environment of a process in the ancestry.
+type ciJob struct {
+	provider *ciProvider
+	pid      int // process carrying the job environment
+	env      []string
+	runner   *model.Process // nil when the agent is no longer an ancestor
+	// adopted is the process at or below the job that was reparented after
+	// its parent exited, the evidence that the runner is gone rather than
+	// outside the PID ancestry (docker executors, container: jobs)
+	adopted *model.Process
+}
+
+// findCIJob returns the job closest to the target, or nil.
+func findCIJob(ancestry []model.Process) *ciJob {
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		env := ancestry[i].Env
+		for j := range ciProviders {
+			provider := &ciProviders[j]
+			if envValue(env, provider.marker) == "" {
+				continue
+			}
+			job := &ciJob{provider: provider, pid: ancestry[i].PID, env: env}
+			for k := i; k >= 0; k-- {
+				if provider.isRunner(ancestry[k]) {
+					job.runner = &ancestry[k]
+					break
+				}
+			}
+			for k := i; k < len(ancestry); k++ {
+				if ancestry[k].Adoption != nil {
+					job.adopted = &ancestry[k]
+					break
+				}
+			}
+			return job
+		}
+	}
+	return nil
+}
+
+func detectCI(ancestry []model.Process) *model.Source {
+	job := findCIJob(ancestry)
+	if job == nil {
+		return nil
+	}
+
+	details := map[string]string{}
+	for k, v := range job.provider.details(job.env) {
+		if v != "" {
+			details[k] = v
+		}
+	}
+	evidence := []string{"pid " + itoa(job.pid) + " has " + job.provider.marker + " in its environment"}
+	confidence := 80
+	if job.runner != nil {
+		details["runner"] = job.runner.Command + " (pid " + itoa(job.runner.PID) + ")"
+		evidence = append(evidence, "ancestor "+itoa(job.runner.PID)+" is "+job.runner.Command)
+	} else if job.adopted != nil {
+		details["runner"] = "exited"
+		evidence = append(evidence, "no "+job.provider.name+" runner in the ancestry and pid "+itoa(job.adopted.PID)+" was reparented")
+		confidence = 60
+	} else {
+		// The runner may live outside the PID ancestry, e.g. the job runs
+		// in a container started by the runner
+		details["runner"] = "not in ancestry"
+		evidence = append(evidence, "no "+job.provider.name+" runner in the ancestry")
+		confidence = 60
+	}
+	return &model.Source{
+		Type:       model.SourceCI,
+		Name:       job.provider.name,
+		Details:    details,
+		Confidence: confidence,
+		Evidence:   evidence,
+	}
+}
+
+// ciLeakWarning reports processes that outlived the CI job that started
+// them. A missing runner alone is not enough: container jobs never have it
+// in their ancestry, so the job must also show reparenting.
+func ciLeakWarning(ancestry []model.Process) string {
+	job := findCIJob(ancestry)
+	if job == nil || job.runner != nil || job.adopted == nil {
+		return ""
+	}
+	what := job.provider.name + " job"
+	if name := job.provider.details(job.env)["job"]; name != "" {
+		what += " " + name
+	}
+	return "Process outlived its " + what + " (runner exited; pid " + itoa(job.adopted.PID) + " was adopted by " + job.adopted.Adoption.AdopterCommand + ")"
+}
@@ -0,0 +1,142 @@
+package source
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDetectCI(t *testing.T) {
+	githubEnv := []string{
+		"GITHUB_ACTIONS=true",
+		"GITHUB_RUN_ID=9876543210",
+		"GITHUB_REPOSITORY=acme/shop",
+		"GITHUB_WORKFLOW=ci",
+		"GITHUB_JOB=integration",
+		"GITHUB_SERVER_URL=https://github.com",
+	}
+
+	tests := []struct {
+		name        string
+		ancestry    []model.Process
+		wantName    string
+		wantDetails map[string]string
+	}{
+		{
+			name: "github actions",
+			ancestry: []model.Process{
+				{PID: 1, Command: "systemd"},
+				{PID: 700, Command: "Runner.Listener"},
+				{PID: 910, Command: "Runner.Worker"},
+				{PID: 950, Command: "bash", Env: githubEnv},
+				{PID: 990, Command: "node", Env: githubEnv},
+			},
+			wantName: "github-actions",
+			wantDetails: map[string]string{
+				"repository": "acme/shop",
+				"workflow":   "ci",
+				"job":        "integration",
+				"run":        "https://github.com/acme/shop/actions/runs/9876543210",
+				"runner":     "Runner.Worker (pid 910)",
+			},
+		},
+		{
+			name: "gitlab shell executor",
+			ancestry: []model.Process{
+				{PID: 1, Command: "systemd"},
+				{PID: 400, Command: "gitlab-runner"},
+				{PID: 1200, Command: "bash", Env: []string{"CI_JOB_ID=4411", "CI_JOB_NAME=deploy", "CI_PIPELINE_ID=812", "CI_PROJECT_PATH=infra/site", "CI_JOB_URL=https://gitlab.example.com/infra/site/-/jobs/4411"}},
+			},
+			wantName: "gitlab-ci",
+			wantDetails: map[string]string{
+				"repository": "infra/site",
+				"workflow":   "pipeline 812",
+				"job":        "deploy",
+				"run":        "https://gitlab.example.com/infra/site/-/jobs/4411",
+				"runner":     "gitlab-runner (pid 400)",
+			},
+		},
+		{
+			name: "jenkins agent",
+			ancestry: []model.Process{
+				{PID: 1, Command: "systemd"},
+				{PID: 300, Command: "java", Cmdline: "java -jar /opt/jenkins/agent.jar -url https://ci.example.com/"},
+				{PID: 1500, Command: "make", Env: []string{"BUILD_URL=https://ci.example.com/job/shop/42/", "JOB_NAME=shop", "BUILD_NUMBER=42", "GIT_URL=git@github.com:acme/shop.git"}},
+			},
+			wantName: "jenkins",
+			wantDetails: map[string]string{
+				"repository": "git@github.com:acme/shop.git",
+				"job":        "shop #42",
+				"run":        "https://ci.example.com/job/shop/42/",
+				"runner":     "java (pid 300)",
+			},
+		},
+		{
+			name: "leaked buildkite job",
+			ancestry: []model.Process{
+				{PID: 1, Command: "systemd"},
+				{PID: 2200, Command: "sleep", Adoption: &model.AdoptionInfo{AdopterPID: 1, AdopterCommand: "systemd"}, Env: []string{"BUILDKITE_JOB_ID=0190-ab", "BUILDKITE_BUILD_URL=https://buildkite.com/acme/shop/builds/77", "BUILDKITE_PIPELINE_SLUG=shop", "BUILDKITE_LABEL=:test: unit"}},
+			},
+			wantName: "buildkite",
+			wantDetails: map[string]string{
+				"workflow": "shop",
+				"job":      ":test: unit",
+				"run":      "https://buildkite.com/acme/shop/builds/77#0190-ab",
+				"runner":   "exited",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := detectCI(tt.ancestry)
+			if src == nil {
+				t.Fatal("detectCI() = nil")
+			}
+			if src.Type != model.SourceCI || src.Name != tt.wantName {
+				t.Fatalf("got %s/%s, want ci/%s", src.Type, src.Name, tt.wantName)
+			}
+			if len(src.Details) != len(tt.wantDetails) {
+				t.Fatalf("Details = %v, want %v", src.Details, tt.wantDetails)
+			}
+			for k, v := range tt.wantDetails {
+				if src.Details[k] != v {
+					t.Errorf("Details[%q] = %q, want %q", k, src.Details[k], v)
+				}
+			}
+		})
+	}
+
+	if src := detectCI([]model.Process{{PID: 1, Command: "systemd"}, {PID: 5, Command: "bash", Env: []string{"HOME=/root"}}}); src != nil {
+		t.Errorf("detectCI() without CI env = %+v", src)
+	}
+}
+
+func TestCILeakWarning(t *testing.T) {
+	env := []string{"GITHUB_RUN_ID=1", "GITHUB_JOB=build"}
+
+	adopted := &model.AdoptionInfo{AdopterPID: 1, AdopterCommand: "systemd"}
+	leaked := []model.Process{{PID: 1, Command: "systemd"}, {PID: 3000, Command: "dockerd-rootless", Env: env, Adoption: adopted}}
+	got := ciLeakWarning(leaked)
+	if !strings.Contains(got, "github-actions job build") || !strings.Contains(got, "pid 3000 was adopted by systemd") {
+		t.Errorf("ciLeakWarning() = %q", got)
+	}
+
+	// Container jobs: the runner is outside the container's PID ancestry
+	for name, chain := range map[string][]model.Process{
+		"gitlab docker executor": {{PID: 1, Command: "sh", Env: []string{"GITLAB_CI=true", "CI_JOB_ID=7"}}, {PID: 30, Command: "make", Env: []string{"GITLAB_CI=true", "CI_JOB_ID=7"}}},
+		"github container job":   {{PID: 1, Command: "tail"}, {PID: 40, Command: "bash", Env: env}},
+	} {
+		if got := ciLeakWarning(chain); got != "" {
+			t.Errorf("%s: ciLeakWarning() = %q", name, got)
+		}
+		if src := detectCI(chain); src == nil || src.Details["runner"] != "not in ancestry" {
+			t.Errorf("%s: detectCI() = %+v", name, src)
+		}
+	}
+
+	running := []model.Process{{PID: 1, Command: "systemd"}, {PID: 910, Command: "Runner.Worker"}, {PID: 3000, Command: "bash", Env: env}}
+	if got := ciLeakWarning(running); got != "" {
+		t.Errorf("ciLeakWarning() with live runner = %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+package source
+
+import (
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+const (
+	// closeWaitThreshold is the number of CLOSE_WAIT sockets above which the
+	// process is assumed to be failing to close connections its peers ended.
+	closeWaitThreshold = 20
+	// recvQueueThreshold is the unread byte count that suggests the process
+	// has stopped reading a socket.
+	recvQueueThreshold = 1 << 20
+	// maxConnectionEvidence caps the evidence lines of a connection warning.
+	maxConnectionEvidence = 5
+)
+
+// connectionWarnings reports sockets that point at a hung consumer: piles
+// of CLOSE_WAIT and large unread receive queues.
+func connectionWarnings(p model.Process) []model.Warning {
+	var w []model.Warning
+
+	closeWait := 0
+	peers := make(map[string]int)
+	var recvEvidence []string
+	for _, c := range p.Connections {
+		if c.State == "CLOSE_WAIT" {
+			closeWait++
+			peers[net.JoinHostPort(c.RemoteAddress, strconv.Itoa(c.RemotePort))]++
+		}
+		// A listener's rx queue counts pending connections, not bytes
+		if c.State != "LISTEN" && c.RxQueue >= recvQueueThreshold {
+			recvEvidence = append(recvEvidence, connectionEvidence(c)+": "+
+				strconv.FormatUint(c.RxQueue, 10)+" bytes unread")
+		}
+	}
+
+	if closeWait >= closeWaitThreshold {
+		w = append(w, model.Warning{
+			ID:       "WITR-CLOSE-WAIT",
+			Severity: model.SeverityMedium,
+			Category: model.CategoryNetwork,
+			Message:  strconv.Itoa(closeWait) + " connections in CLOSE_WAIT; peers closed them but the process has not (leaked or hung connections)",
+			Evidence: closeWaitEvidence(peers),
+		})
+	}
+	if len(recvEvidence) > 0 {
+		w = append(w, model.Warning{
+			ID:       "WITR-RECV-QUEUE",
+			Severity: model.SeverityMedium,
+			Category: model.CategoryNetwork,
+			Message:  strconv.Itoa(len(recvEvidence)) + " socket(s) with over 1 MiB of unread data; the process is not keeping up with its input",
+			Evidence: capEvidence(recvEvidence),
+		})
+	}
+	return w
+}
+
+func connectionEvidence(c model.Socket) string {
+	line := c.Protocol + " " + net.JoinHostPort(c.Address, strconv.Itoa(c.Port))
+	if c.RemotePort != 0 {
+		line += " -> " + net.JoinHostPort(c.RemoteAddress, strconv.Itoa(c.RemotePort))
+	}
+	return line
+}
+
+// closeWaitEvidence lists the peers with the most CLOSE_WAIT sockets.
+func closeWaitEvidence(peers map[string]int) []string {
+	keys := make([]string, 0, len(peers))
+	for peer := range peers {
+		keys = append(keys, peer)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if peers[keys[i]] != peers[keys[j]] {
+			return peers[keys[i]] > peers[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	evidence := make([]string, 0, len(keys))
+	for _, peer := range keys {
+		evidence = append(evidence, strconv.Itoa(peers[peer])+" from "+peer)
+	}
+	return capEvidence(evidence)
+}
+
+func capEvidence(evidence []string) []string {
+	if len(evidence) > maxConnectionEvidence {
+		more := len(evidence) - maxConnectionEvidence
+		evidence = append(evidence[:maxConnectionEvidence:maxConnectionEvidence], "... and "+strconv.Itoa(more)+" more")
+	}
+	return evidence
+}
@@ -0,0 +1,64 @@
+package source
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestConnectionWarnings(t *testing.T) {
+	established := model.Socket{Protocol: "TCP", Address: "10.0.0.5", Port: 8080, RemoteAddress: "10.0.0.9", RemotePort: 40000, State: "ESTABLISHED"}
+
+	closeWait := func(n int, peer string) []model.Socket {
+		var conns []model.Socket
+		for i := range n {
+			conns = append(conns, model.Socket{Protocol: "TCP", Address: "10.0.0.5", Port: 50000 + i, RemoteAddress: peer, RemotePort: 5432, State: "CLOSE_WAIT"})
+		}
+		return conns
+	}
+
+	t.Run("healthy", func(t *testing.T) {
+		p := model.Process{Connections: append(closeWait(closeWaitThreshold-1, "10.0.0.7"), established,
+			model.Socket{Protocol: "TCP", Port: 8080, State: "LISTEN", RxQueue: recvQueueThreshold})}
+		if w := connectionWarnings(p); len(w) != 0 {
+			t.Errorf("got %v, want none", w)
+		}
+	})
+
+	t.Run("close wait", func(t *testing.T) {
+		conns := append(closeWait(15, "10.0.0.7"), closeWait(10, "10.0.0.8")...)
+		w := connectionWarnings(model.Process{Connections: conns})
+		if len(w) != 1 || w[0].ID != "WITR-CLOSE-WAIT" {
+			t.Fatalf("got %v", w)
+		}
+		if want := []string{"15 from 10.0.0.7:5432", "10 from 10.0.0.8:5432"}; !slices.Equal(w[0].Evidence, want) {
+			t.Errorf("Evidence = %v, want %v", w[0].Evidence, want)
+		}
+	})
+
+	t.Run("receive queue", func(t *testing.T) {
+		stuck := established
+		stuck.RxQueue = 4 << 20
+		w := connectionWarnings(model.Process{Connections: []model.Socket{established, stuck}})
+		if len(w) != 1 || w[0].ID != "WITR-RECV-QUEUE" {
+			t.Fatalf("got %v", w)
+		}
+		want := "TCP 10.0.0.5:8080 -> 10.0.0.9:40000: " + strconv.Itoa(4<<20) + " bytes unread"
+		if !slices.Equal(w[0].Evidence, []string{want}) {
+			t.Errorf("Evidence = %v", w[0].Evidence)
+		}
+	})
+}
+
+func TestCapEvidence(t *testing.T) {
+	var evidence []string
+	for i := range maxConnectionEvidence + 3 {
+		evidence = append(evidence, strconv.Itoa(i))
+	}
+	got := capEvidence(evidence)
+	if len(got) != maxConnectionEvidence+1 || got[maxConnectionEvidence] != "... and 3 more" {
+		t.Errorf("capEvidence = %v", got)
+	}
+}
@@ -2,51 +2,197 @@ package source
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 func detectContainer(ancestry []model.Process) *model.Source {
 	for _, p := range ancestry {
-		data, err := os.ReadFile("/proc/" + itoa(p.PID) + "/cgroup")
+		// An exited ancestor's PID may since belong to an unrelated process
+		if p.Exited {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(procDir, itoa(p.PID), "cgroup"))
 		if err != nil {
 			continue
 		}
-		content := string(data)
+		if src := containerFromCgroup(ancestry, string(data)); src != nil {
+			src.Evidence = append(src.Evidence, "cgroup of pid "+itoa(p.PID)+" is "+cgroupPath(string(data)))
+			return src
+		}
+	}
+	return nil
+}
 
-		switch {
-		case strings.Contains(content, "docker"):
-			return &model.Source{
-				Type: model.SourceContainer,
-				Name: "docker",
-			}
-		case strings.Contains(content, "podman"), strings.Contains(content, "libpod"):
-			return &model.Source{
-				Type: model.SourceContainer,
-				Name: "podman",
-			}
-		case strings.Contains(content, "kubepods"):
-			return &model.Source{
-				Type: model.SourceContainer,
-				Name: "kubernetes",
-			}
-		case strings.Contains(content, "colima"):
-			return &model.Source{
-				Type: model.SourceContainer,
-				Name: "colima",
-			}
-		case strings.Contains(content, "containerd"):
-			return &model.Source{
-				Type: model.SourceContainer,
-				Name: "containerd",
-			}
+// cgroupPath returns the unified (or first) hierarchy path of a cgroup file.
+func cgroupPath(content string) string {
+	first := ""
+	for _, line := range strings.Split(content, "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			return parts[2]
+		}
+		if first == "" {
+			first = parts[2]
+		}
+	}
+	return first
+}
+
+func containerFromCgroup(ancestry []model.Process, content string) *model.Source {
+	switch {
+	case strings.Contains(content, "kubepods"):
+		return &model.Source{
+			Type:    model.SourceContainer,
+			Name:    "kubernetes",
+			Details: kubePodDetails(ancestry),
+		}
+	case strings.Contains(content, "/lxc.payload"), strings.Contains(content, "/lxc/"):
+		return systemContainerSource(ancestry, "lxc")
+	case procpkg.NspawnMachine(content) != "":
+		return systemContainerSource(ancestry, "nspawn")
+	case strings.Contains(content, "docker"):
+		return &model.Source{
+			Type:    model.SourceContainer,
+			Name:    "docker",
+			Details: containerInfoDetails(ancestry),
+		}
+	case strings.Contains(content, "podman"), strings.Contains(content, "libpod"):
+		return &model.Source{
+			Type: model.SourceContainer,
+			Name: "podman",
+		}
+	case strings.Contains(content, "colima"):
+		return &model.Source{
+			Type: model.SourceContainer,
+			Name: "colima",
+		}
+	case strings.Contains(content, "containerd"):
+		return &model.Source{
+			Type: model.SourceContainer,
+			Name: "containerd",
+		}
+	}
+	return nil
+}
+
+// kubePodDetails reports the pod identity of the process closest to the target.
+func kubePodDetails(ancestry []model.Process) map[string]string {
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		pod := ancestry[i].Pod
+		if pod == nil {
+			continue
+		}
+		details := map[string]string{}
+		if pod.Namespace != "" {
+			details["namespace"] = pod.Namespace
+		}
+		if pod.Name != "" {
+			details["pod"] = pod.Name
+		}
+		if pod.Container != "" {
+			details["container"] = pod.Container
+		}
+		if pod.QoSClass != "" {
+			details["qos"] = pod.QoSClass
 		}
+		if pod.Container != "" {
+			details["restarts"] = strconv.Itoa(pod.RestartCount)
+		}
+		if pod.OwnerKind != "" {
+			details["owner"] = pod.OwnerKind + "/" + pod.OwnerName
+		}
+		return details
+	}
+	return nil
+}
+
+// containerInfoDetails reports runtime metadata of the process closest to the target.
+func containerInfoDetails(ancestry []model.Process) map[string]string {
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		info := ancestry[i].ContainerInfo
+		if info == nil {
+			continue
+		}
+		details := map[string]string{}
+		if info.Image != "" {
+			details["image"] = info.Image
+		}
+		if info.ComposeFiles != "" {
+			details["compose"] = info.ComposeFiles
+		}
+		if info.RestartPolicy != "" {
+			details["restart"] = info.RestartPolicy
+		}
+		if info.HealthStatus != "" {
+			details["health"] = info.HealthStatus
+		}
+		if info.RestartCount > 0 {
+			details["restarts"] = itoa(info.RestartCount)
+		}
+		return details
 	}
 	return nil
 }
 
+// systemContainerSource describes an LXC/LXD/Incus container or nspawn
+// machine, including the host-side monitor that spawned its init.
+func systemContainerSource(ancestry []model.Process, runtime string) *model.Source {
+	src := &model.Source{
+		Type: model.SourceContainer,
+		Name: runtime,
+	}
+	details := map[string]string{}
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		info := ancestry[i].ContainerInfo
+		if info == nil {
+			continue
+		}
+		src.Name = info.Runtime
+		src.UnitFile = info.ConfigPath
+		if info.Runtime == "nspawn" {
+			details["machine"] = info.Name
+		} else {
+			details["container"] = info.Name
+		}
+		break
+	}
+	if src.Name == "nspawn" {
+		src.Name = "systemd-nspawn"
+	}
+	if monitor := systemContainerMonitor(ancestry); monitor != "" {
+		details["monitor"] = monitor
+	}
+	if len(details) > 0 {
+		src.Details = details
+	}
+	return src
+}
+
+// systemContainerMonitor finds the host process supervising the container:
+// "[lxc monitor] <path> <name>" for LXC/LXD/Incus, systemd-nspawn for machines.
+func systemContainerMonitor(ancestry []model.Process) string {
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		p := ancestry[i]
+		switch {
+		case strings.HasPrefix(p.Cmdline, "[lxc monitor]"):
+			return "lxc monitor (pid " + itoa(p.PID) + ")"
+		case p.Command == "lxc-start":
+			return "lxc-start (pid " + itoa(p.PID) + ")"
+		case p.Command == "systemd-nspawn":
+			return "systemd-nspawn (pid " + itoa(p.PID) + ")"
+		}
+	}
+	return ""
+}
+
 func itoa(n int) string {
 	return strconv.Itoa(n)
 }
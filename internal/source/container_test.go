@@ -0,0 +1,111 @@
+package source
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestSystemContainerSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		runtime     string
+		ancestry    []model.Process
+		wantName    string
+		wantConfig  string
+		wantDetails map[string]string
+	}{
+		{
+			name:    "lxd container",
+			runtime: "lxc",
+			ancestry: []model.Process{
+				{PID: 1, Command: "systemd"},
+				{PID: 812, Command: "lxd", Cmdline: "[lxc monitor] /var/snap/lxd/common/lxd/containers web01"},
+				{PID: 830, Command: "systemd", ContainerInfo: &model.ContainerInfo{Runtime: "lxd", Name: "web01", ConfigPath: "/var/snap/lxd/common/lxd/containers/web01/backup.yaml"}},
+				{PID: 1204, Command: "nginx", ContainerInfo: &model.ContainerInfo{Runtime: "lxd", Name: "web01", ConfigPath: "/var/snap/lxd/common/lxd/containers/web01/backup.yaml"}},
+			},
+			wantName:    "lxd",
+			wantConfig:  "/var/snap/lxd/common/lxd/containers/web01/backup.yaml",
+			wantDetails: map[string]string{"container": "web01", "monitor": "lxc monitor (pid 812)"},
+		},
+		{
+			name:    "nspawn machine",
+			runtime: "nspawn",
+			ancestry: []model.Process{
+				{PID: 1, Command: "systemd"},
+				{PID: 540, Command: "systemd-nspawn"},
+				{PID: 552, Command: "sshd", ContainerInfo: &model.ContainerInfo{Runtime: "nspawn", Name: "debian", ConfigPath: "/etc/systemd/nspawn/debian.nspawn"}},
+			},
+			wantName:    "systemd-nspawn",
+			wantConfig:  "/etc/systemd/nspawn/debian.nspawn",
+			wantDetails: map[string]string{"machine": "debian", "monitor": "systemd-nspawn (pid 540)"},
+		},
+		{
+			name:     "no metadata",
+			runtime:  "lxc",
+			ancestry: []model.Process{{PID: 1, Command: "systemd"}, {PID: 90, Command: "sleep"}},
+			wantName: "lxc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := systemContainerSource(tt.ancestry, tt.runtime)
+			if src.Type != model.SourceContainer || src.Name != tt.wantName || src.UnitFile != tt.wantConfig {
+				t.Fatalf("got %s/%s config %q, want container/%s config %q", src.Type, src.Name, src.UnitFile, tt.wantName, tt.wantConfig)
+			}
+			if len(src.Details) != len(tt.wantDetails) {
+				t.Fatalf("Details = %v, want %v", src.Details, tt.wantDetails)
+			}
+			for k, v := range tt.wantDetails {
+				if src.Details[k] != v {
+					t.Errorf("Details[%q] = %q, want %q", k, src.Details[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectContainerSkipsExitedAncestors(t *testing.T) {
+	orig := procDir
+	procDir = filepath.Join(t.TempDir(), "proc")
+	t.Cleanup(func() { procDir = orig })
+	writeFile(t, filepath.Join(procDir, "4100", "cgroup"), "0::/system.slice/docker-3f4e5d6c7b8a9f0e1d2c3b4a5f6e7d8c9b0a1f2e3d4c5b6a7f8e9d0c1b2a3f4e.scope\n")
+	writeFile(t, filepath.Join(procDir, "4200", "cgroup"), "0::/user.slice/user-1000.slice/session-2.scope\n")
+
+	// PID 4100 was reused by a container process after the ancestor exited
+	ancestry := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 4100, Command: "make", Exited: true},
+		{PID: 4200, Command: "sleep"},
+	}
+	if src := detectContainer(ancestry); src != nil {
+		t.Errorf("detectContainer() through an exited ancestor = %+v", src)
+	}
+
+	ancestry[1].Exited = false
+	if src := detectContainer(ancestry); src == nil {
+		t.Error("detectContainer() on a live container ancestor = nil")
+	}
+}
+
+func TestContainerFromCgroupNspawn(t *testing.T) {
+	tests := []struct {
+		cgroup string
+		want   string
+	}{
+		{"0::/machine.slice/machine-debian.scope/payload\n", "systemd-nspawn"},
+		{"0::/machine.slice/systemd-nspawn@fedora.service/payload\n", "systemd-nspawn"},
+		{"0::/machine.slice/machine-qemu\\x2d1\\x2dwin11.scope/libvirt/emulator\n", ""},
+		{"0::/machine.slice/machine-lxc\\x2d4211\\x2dweb.scope\n", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if src := containerFromCgroup(nil, tt.cgroup); src != nil {
+			got = src.Name
+		}
+		if got != tt.want {
+			t.Errorf("containerFromCgroup(%q) = %q, want %q", tt.cgroup, got, tt.want)
+		}
+	}
+}
@@ -6,8 +6,9 @@ func detectCron(ancestry []model.Process) *model.Source {
 	for _, p := range ancestry {
 		if p.Command == "cron" || p.Command == "crond" {
 			return &model.Source{
-				Type: model.SourceCron,
-				Name: "cron",
+				Type:     model.SourceCron,
+				Name:     "cron",
+				Evidence: []string{"ancestor " + itoa(p.PID) + " is " + p.Command},
 			}
 		}
 	}
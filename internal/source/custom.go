@@ -0,0 +1,109 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pranshuparmar/witr/internal/rules"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// customRules holds the detectors and warnings from rules.yaml files.
+// Invalid rules are skipped here; `witr rules check` reports them.
+var customRules = sync.OnceValue(func() *rules.Set {
+	set, _ := rules.Load(rules.DefaultPaths())
+	return set
+})
+
+// detectCustom returns one candidate per user-defined detector, matched
+// against the closest ancestor first.
+func detectCustom(ancestry []model.Process) []model.Source {
+	set := customRules()
+	if set == nil || len(set.Detectors) == 0 {
+		return nil
+	}
+
+	cgroups := map[int]string{}
+	var candidates []model.Source
+	for _, d := range set.Detectors {
+		for i := len(ancestry) - 1; i >= 0; i-- {
+			p := ancestry[i]
+			cgroup := ""
+			if d.Match.NeedsCgroup() {
+				// The PID of an exited ancestor may have been reused
+				if p.Exited {
+					continue
+				}
+				cgroup = cachedCgroup(cgroups, p.PID)
+			}
+			if sub, ok := d.Match.Matches(p, cgroup); ok {
+				candidates = append(candidates, d.BuildSource(p, sub))
+				break
+			}
+		}
+	}
+	return candidates
+}
+
+// customWarnings renders the user-defined warnings that match the target.
+func customWarnings(p model.Process) []model.Warning {
+	set := customRules()
+	if set == nil {
+		return nil
+	}
+
+	cgroups := map[int]string{}
+	var w []model.Warning
+	for _, rule := range set.Warnings {
+		cgroup := ""
+		if rule.Match.NeedsCgroup() {
+			cgroup = cachedCgroup(cgroups, p.PID)
+		}
+		if sub, ok := rule.Match.Matches(p, cgroup); ok {
+			if warning := rule.Build(p, sub); warning.Message != "" {
+				w = append(w, warning)
+			}
+		}
+	}
+	return w
+}
+
+func cachedCgroup(cache map[int]string, pid int) string {
+	if cg, ok := cache[pid]; ok {
+		return cg
+	}
+	data, _ := os.ReadFile(filepath.Join(procDir, itoa(pid), "cgroup"))
+	cache[pid] = string(data)
+	return cache[pid]
+}
+
+// SuppressWarnings drops warnings whose ID is in ids or matches an ignore
+// entry from the rules files. It returns the kept warnings and the number
+// dropped.
+func SuppressWarnings(w []model.Warning, ids []string, src model.Source, p model.Process) ([]model.Warning, int) {
+	var ignore []*rules.Ignore
+	if set := customRules(); set != nil {
+		ignore = set.Ignore
+	}
+	if len(ids) == 0 && len(ignore) == 0 {
+		return w, 0
+	}
+
+	unit := p.Service
+	if unit == "" && src.Type == model.SourceSystemd && src.UnitFile != "" {
+		unit = filepath.Base(src.UnitFile)
+	}
+
+	kept := w[:0:0]
+	for _, warning := range w {
+		if slices.ContainsFunc(ids, func(id string) bool { return strings.EqualFold(id, warning.ID) }) ||
+			slices.ContainsFunc(ignore, func(ig *rules.Ignore) bool { return ig.Matches(warning.ID, src, unit, p.Exe) }) {
+			continue
+		}
+		kept = append(kept, warning)
+	}
+	return kept, len(w) - len(kept)
+}
@@ -1,7 +1,10 @@
 package source
 
 import (
+	"net"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -9,6 +12,7 @@ import (
 )
 
 type envSuspiciousRule struct {
+	id          string
 	pattern     string
 	match       func(key, pattern string) bool
 	warning     string
@@ -18,12 +22,14 @@ type envSuspiciousRule struct {
 var (
 	envVarRules = []envSuspiciousRule{
 		{
+			id:      "WITR-LD-PRELOAD",
 			pattern: "LD_PRELOAD",
 			match:   func(key, pattern string) bool { return key == pattern },
 			warning: "Process sets LD_PRELOAD (potential library injection)",
 		},
 
 		{
+			id:          "WITR-DYLD-INJECTION",
 			pattern:     "DYLD_",
 			match:       strings.HasPrefix,
 			warning:     "Process sets DYLD_* variables (potential library injection)",
@@ -32,46 +38,78 @@ var (
 	}
 )
 
+// defaultConfidence scores candidates whose detector had no reason to
+// set its own. Higher means the attribution is more specific.
+var defaultConfidence = map[model.SourceType]int{
+	model.SourceKernel:         100,
+	model.SourceContainer:      95,
+	model.SourceSandbox:        85,
+	model.SourceCI:             80,
+	model.SourceMultiplexer:    75,
+	model.SourceSupervisor:     70,
+	model.SourceCron:           65,
+	model.SourceShell:          60,
+	model.SourceWindowsService: 50,
+	model.SourceLaunchd:        40,
+	model.SourceBsdRc:          40,
+	model.SourceSystemd:        20,
+	model.SourceInit:           20,
+}
+
+// Detect returns the most likely source of the process.
 func Detect(ancestry []model.Process) model.Source {
-	// Detection order prioritizes platform-specific init systems
-	// over generic supervisor detection to avoid false positives
-	if src := detectContainer(ancestry); src != nil {
-		return *src
-	}
-	if src := detectShell(ancestry); src != nil {
-		return *src
-	}
-	if src := detectSystemd(ancestry); src != nil {
-		return *src
-	}
-	if src := detectLaunchd(ancestry); src != nil {
-		return *src
-	}
-	if src := detectBsdRc(ancestry); src != nil {
-		return *src
+	if all := DetectAll(ancestry); len(all) > 0 {
+		return all[0]
 	}
-	if src := detectSupervisor(ancestry); src != nil {
-		return *src
-	}
-	if src := detectCron(ancestry); src != nil {
-		return *src
-	}
-	if src := detectWindowsService(ancestry); src != nil {
-		return *src
+	return model.Source{
+		Type: model.SourceUnknown,
 	}
-	if src := detectInit(ancestry); src != nil {
-		return *src
+}
+
+// DetectAll runs every detector and returns the candidates ordered by
+// confidence, best first. Ties keep detector order, which prioritizes
+// platform-specific init systems over generic supervisor detection.
+func DetectAll(ancestry []model.Process) []model.Source {
+	detectors := []func([]model.Process) *model.Source{
+		detectKernel,
+		detectContainer,
+		detectSandbox,
+		detectCI,
+		detectMultiplexer,
+		detectShell,
+		detectSystemd,
+		detectLaunchd,
+		detectBsdRc,
+		detectSupervisor,
+		detectCron,
+		detectWindowsService,
+		detectInit,
 	}
 
-	return model.Source{
-		Type: model.SourceUnknown,
+	// User-defined detectors go first so they win ties with built-ins
+	candidates := detectCustom(ancestry)
+	for _, detect := range detectors {
+		src := detect(ancestry)
+		if src == nil {
+			continue
+		}
+		if src.Confidence == 0 {
+			src.Confidence = defaultConfidence[src.Type]
+		}
+		candidates = append(candidates, *src)
 	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Confidence > candidates[j].Confidence
+	})
+	return candidates
 }
 
 // env suspicious warnings returns warnings for known env based library injection patterns
-func envSuspiciousWarnings(env []string) []string {
+func envSuspiciousWarnings(env []string) []model.Warning {
 	matched := make([]bool, len(envVarRules))
 	matchedKeys := make([]map[string]struct{}, len(envVarRules))
+	evidence := make([][]string, len(envVarRules))
 
 	// init per rule key capture only for rules that include keys
 	for i, rule := range envVarRules {
@@ -93,21 +131,31 @@ func envSuspiciousWarnings(env []string) []string {
 				continue
 			}
 			matched[i] = true
+			if !slices.Contains(evidence[i], entry) {
+				evidence[i] = append(evidence[i], entry)
+			}
 			if rule.includeKeys {
 				matchedKeys[i][key] = struct{}{}
 			}
 		}
 	}
 
-	var warnings []string
+	var warnings []model.Warning
 
 	// emit warnings in the same order as envVarRules
 	for i, rule := range envVarRules {
 		if !matched[i] {
 			continue
 		}
+		warning := model.Warning{
+			ID:       rule.id,
+			Severity: model.SeverityHigh,
+			Category: model.CategorySecurity,
+			Message:  rule.warning,
+			Evidence: evidence[i],
+		}
 		if !rule.includeKeys {
-			warnings = append(warnings, rule.warning)
+			warnings = append(warnings, warning)
 			continue
 		}
 
@@ -117,16 +165,23 @@ func envSuspiciousWarnings(env []string) []string {
 			keys = append(keys, key)
 		}
 		sort.Strings(keys)
-		warnings = append(warnings, rule.warning+": "+strings.Join(keys, ", "))
+		warning.Message += ": " + strings.Join(keys, ", ")
+		warnings = append(warnings, warning)
 	}
 
 	return warnings
 }
 
-func Warnings(p []model.Process) []string {
-	var w []string
+// Warnings returns the warnings for the target at the end of p. src is the
+// source already detected for p, so detectors (and the commands some of
+// them run) are not invoked a second time.
+func Warnings(p []model.Process, src model.Source) []model.Warning {
+	var w []model.Warning
 
 	last := p[len(p)-1]
+	if last.KernelThread {
+		return kernelThreadWarnings(last)
+	}
 
 	// Restart count detection (count consecutive same-command entries)
 	restartCount := 0
@@ -138,65 +193,186 @@ func Warnings(p []model.Process) []string {
 		lastCmd = proc.Command
 	}
 	if restartCount > 5 {
-		w = append(w, "Process or ancestor restarted more than 5 times")
+		w = append(w, model.Warning{
+			ID:       "WITR-RESTART-LOOP",
+			Severity: model.SeverityMedium,
+			Category: model.CategoryReliability,
+			Message:  "Process or ancestor restarted more than 5 times",
+			Evidence: []string{strconv.Itoa(restartCount) + " consecutive ancestors run the same command"},
+		})
 	}
 
 	// Health warnings
 	switch last.Health {
 	case "zombie":
-		w = append(w, "Process is a zombie (defunct)")
+		msg := "Process is a zombie (defunct)"
+		if i := LiveParentIndex(p, last); i >= 0 {
+			parent := p[i]
+			msg += "; parent " + parent.Command + " (pid " + strconv.Itoa(parent.PID) + ") has not reaped it"
+		} else if last.PPID > 0 {
+			msg += "; parent pid " + strconv.Itoa(last.PPID) + " has not reaped it"
+		}
+		w = append(w, model.Warning{ID: "WITR-ZOMBIE", Severity: model.SeverityMedium, Category: model.CategoryReliability, Message: msg})
 	case "stopped":
-		w = append(w, "Process is stopped (T state)")
+		w = append(w, model.Warning{ID: "WITR-STOPPED", Severity: model.SeverityLow, Category: model.CategoryReliability, Message: "Process is stopped (T state)"})
 	case "high-cpu":
-		w = append(w, "Process is using high CPU (>2h total)")
+		w = append(w, model.Warning{ID: "WITR-HIGH-CPU", Severity: model.SeverityLow, Category: model.CategoryResources, Message: "Process is using high CPU (>2h total)"})
 	case "high-mem":
-		w = append(w, "Process is using high memory (>1GB RSS)")
+		w = append(w, model.Warning{ID: "WITR-HIGH-MEM", Severity: model.SeverityLow, Category: model.CategoryResources, Message: "Process is using high memory (>1GB RSS)"})
 	}
 
 	if IsPublicBind(last.BindAddresses) {
-		w = append(w, "Process is listening on a public interface")
+		w = append(w, model.Warning{
+			ID:       "WITR-PUBLIC-BIND",
+			Severity: model.SeverityMedium,
+			Category: model.CategoryNetwork,
+			Message:  "Process is listening on a public interface",
+			Evidence: publicBindEvidence(last),
+		})
 	}
 
+	// Include CLOSE_WAIT and socket queue warnings
+	w = append(w, connectionWarnings(last)...)
+
 	if last.User == "root" {
-		w = append(w, "Process is running as root")
+		w = append(w, model.Warning{ID: "WITR-ROOT", Severity: model.SeverityLow, Category: model.CategorySecurity, Message: "Process is running as root"})
 	}
 
-	if Detect(p).Type == model.SourceUnknown {
-		w = append(w, "No known supervisor or service manager detected")
+	if src.Type == model.SourceUnknown {
+		w = append(w, model.Warning{ID: "WITR-NO-SUPERVISOR", Severity: model.SeverityLow, Category: model.CategoryReliability, Message: "No known supervisor or service manager detected"})
 	}
 
 	// Warn if process is very old (>90 days)
 	if time.Since(last.StartedAt).Hours() > 90*24 {
-		w = append(w, "Process has been running for over 90 days")
+		w = append(w, model.Warning{
+			ID:       "WITR-LONG-RUNNING",
+			Severity: model.SeverityInfo,
+			Category: model.CategoryReliability,
+			Message:  "Process has been running for over 90 days",
+			Evidence: []string{"started " + last.StartedAt.Format(time.RFC3339)},
+		})
 	}
 
 	// Warn if working dir is suspicious
 	suspiciousDirs := map[string]bool{"/": true, "/tmp": true, "/var/tmp": true}
 	if suspiciousDirs[last.WorkingDir] {
-		w = append(w, "Process is running from a suspicious working directory: "+last.WorkingDir)
+		w = append(w, model.Warning{ID: "WITR-SUSPICIOUS-CWD", Severity: model.SeverityLow, Category: model.CategorySecurity, Message: "Process is running from a suspicious working directory: " + last.WorkingDir})
 	}
 
-	// Warn if container and no healthcheck (placeholder, as healthcheck not detected)
-	if last.Container != "" {
-		w = append(w, "No healthcheck detected for container (best effort)")
+	// Warn if container and no healthcheck (best effort unless runtime metadata is known)
+	if info := last.ContainerInfo; info != nil {
+		if info.HealthCheck == "" {
+			w = append(w, model.Warning{ID: "WITR-NO-HEALTHCHECK", Severity: model.SeverityLow, Category: model.CategoryReliability, Message: "No healthcheck configured for container"})
+		} else if info.HealthStatus == "unhealthy" {
+			w = append(w, model.Warning{
+				ID:       "WITR-UNHEALTHY",
+				Severity: model.SeverityHigh,
+				Category: model.CategoryReliability,
+				Message:  "Container healthcheck is failing",
+				Evidence: []string{"healthcheck: " + info.HealthCheck},
+			})
+		}
+	} else if last.Container != "" {
+		w = append(w, model.Warning{ID: "WITR-NO-HEALTHCHECK", Severity: model.SeverityLow, Category: model.CategoryReliability, Message: "No healthcheck detected for container (best effort)"})
+	}
+
+	if warning := ciLeakWarning(p); warning != "" {
+		w = append(w, model.Warning{ID: "WITR-CI-LEAK", Severity: model.SeverityMedium, Category: model.CategoryReliability, Message: warning})
 	}
 
 	// Warn if service name and process name mismatch
 	if last.Service != "" && last.Command != "" && last.Service != last.Command {
-		w = append(w, "Service name and process name do not match")
+		w = append(w, model.Warning{
+			ID:       "WITR-SERVICE-MISMATCH",
+			Severity: model.SeverityInfo,
+			Category: model.CategoryConfig,
+			Message:  "Service name and process name do not match",
+			Evidence: []string{"service " + last.Service + ", process " + last.Command},
+		})
 	}
 
 	// Warn if binary is deleted
 	if last.ExeDeleted {
-		w = append(w, "Process is running from a deleted binary (potential library injection or pending update)")
+		w = append(w, model.Warning{
+			ID:       "WITR-DELETED-EXE",
+			Severity: model.SeverityHigh,
+			Category: model.CategoryIntegrity,
+			Message:  "Process is running from a deleted binary (potential library injection or pending update)",
+			Evidence: exeEvidence(last),
+		})
+	}
+
+	// Warn if the executable no longer matches what its package installed
+	if pkg := last.Package; pkg != nil && pkg.Integrity == model.IntegrityModified {
+		w = append(w, model.Warning{
+			ID:       "WITR-PKG-MODIFIED",
+			Severity: model.SeverityHigh,
+			Category: model.CategoryIntegrity,
+			Message:  "Executable differs from the digest recorded by package " + pkg.Name + " (" + pkg.Manager + "); it was modified after installation",
+			Evidence: exeEvidence(last),
+		})
+	}
+	if last.ExeReplaced {
+		w = append(w, model.Warning{
+			ID:       "WITR-EXE-REPLACED",
+			Severity: model.SeverityHigh,
+			Category: model.CategoryIntegrity,
+			Message:  "Running executable no longer matches the file on disk (replaced after the process started)",
+			Evidence: exeEvidence(last),
+		})
+	}
+
+	// Shells and interpreters anywhere in the chain with stdio on a socket
+	for _, proc := range p {
+		if warning, ok := NetworkShellWarning(proc, p); ok {
+			w = append(w, warning)
+		}
 	}
 
+	// Include privilege and confinement warnings
+	w = append(w, securityWarnings(last)...)
+
+	// Include masquerading and fileless execution warnings
+	w = append(w, executableWarnings(last)...)
+
 	// Include warnings based on suspicious env variables
 	w = append(w, envSuspiciousWarnings(last.Env)...)
 
+	// Include warnings from user-defined rules
+	w = append(w, customWarnings(last)...)
+
 	return w
 }
 
+func publicBindEvidence(p model.Process) []string {
+	var evidence []string
+	for i, addr := range p.BindAddresses {
+		if (addr == "0.0.0.0" || addr == "::") && i < len(p.ListeningPorts) {
+			evidence = append(evidence, "listening on "+net.JoinHostPort(addr, strconv.Itoa(p.ListeningPorts[i])))
+		}
+	}
+	return evidence
+}
+
+// LiveParentIndex returns the index in ancestry of p's running parent, or
+// -1. Exited ancestors that reconstruction spliced into the chain are
+// skipped, so the result is the process that actually owns p now.
+func LiveParentIndex(ancestry []model.Process, p model.Process) int {
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		if ancestry[i].PID == p.PPID && !ancestry[i].Exited {
+			return i
+		}
+	}
+	return -1
+}
+
+func exeEvidence(p model.Process) []string {
+	if p.Exe == "" {
+		return nil
+	}
+	return []string{"exe " + p.Exe}
+}
+
 // EnrichSocketInfo provides human-readable explanations and workarounds for socket states
 func EnrichSocketInfo(si *model.SocketInfo) {
 	if si == nil {
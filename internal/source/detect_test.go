@@ -1,14 +1,24 @@
 package source
 
 import (
+	"path/filepath"
 	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pranshuparmar/witr/internal/rules"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
+func warningMessages(w []model.Warning) []string {
+	var msgs []string
+	for _, warning := range w {
+		msgs = append(msgs, warning.Message)
+	}
+	return msgs
+}
+
 func TestWarningsDetectsLDPreload(t *testing.T) {
 	p := []model.Process{
 		{PID: 999999, Command: "pm2", Cmdline: "pm2"},
@@ -22,7 +32,7 @@ func TestWarningsDetectsLDPreload(t *testing.T) {
 		},
 	}
 
-	warnings := Warnings(p)
+	warnings := warningMessages(Warnings(p, Detect(p)))
 	if !slices.Contains(warnings, "Process sets LD_PRELOAD (potential library injection)") {
 		t.Fatalf("expected LD_PRELOAD warning, got: %v", warnings)
 	}
@@ -44,7 +54,7 @@ func TestWarningsDetectsDYLDVars(t *testing.T) {
 		},
 	}
 
-	warnings := Warnings(p)
+	warnings := warningMessages(Warnings(p, Detect(p)))
 	want := "Process sets DYLD_* variables (potential library injection): DYLD_INSERT_LIBRARIES, DYLD_LIBRARY_PATH"
 	if !slices.Contains(warnings, want) {
 		t.Fatalf("expected DYLD warning %q, got: %v", want, warnings)
@@ -67,7 +77,7 @@ func TestWarningsIgnoresEmptyPreloadVars(t *testing.T) {
 		},
 	}
 
-	warnings := Warnings(p)
+	warnings := warningMessages(Warnings(p, Detect(p)))
 	if slices.Contains(warnings, "Process sets LD_PRELOAD (potential library injection)") {
 		t.Fatalf("did not expect LD_PRELOAD warning, got: %v", warnings)
 	}
@@ -94,8 +104,8 @@ func FuzzEnvSuspiciousWarningsDeterministic(f *testing.F) {
 			}
 		}
 
-		w1 := envSuspiciousWarnings(parts)
-		w2 := envSuspiciousWarnings(parts)
+		w1 := warningMessages(envSuspiciousWarnings(parts))
+		w2 := warningMessages(envSuspiciousWarnings(parts))
 		if !slices.Equal(w1, w2) {
 			t.Fatalf("expected deterministic output, got %v vs %v", w1, w2)
 		}
@@ -143,7 +153,7 @@ func TestEnvSuspiciousWarnings(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := envSuspiciousWarnings(tt.env)
+			got := warningMessages(envSuspiciousWarnings(tt.env))
 			if !slices.Equal(got, tt.want) {
 				t.Fatalf("got %v, want %v", got, tt.want)
 			}
@@ -173,7 +183,7 @@ func FuzzWarningsNoPanic(f *testing.F) {
 			},
 		}
 
-		_ = Warnings(p)
+		_ = Warnings(p, Detect(p))
 	})
 }
 
@@ -187,13 +197,41 @@ func TestWarningsDetectsDeletedExecutable(t *testing.T) {
 		},
 	}
 
-	warnings := Warnings(p)
+	warnings := warningMessages(Warnings(p, Detect(p)))
 	want := "Process is running from a deleted binary (potential library injection or pending update)"
 	if !slices.Contains(warnings, want) {
 		t.Fatalf("expected deleted binary warning, got: %v", warnings)
 	}
 }
 
+func TestWarningsDetectsModifiedPackageBinary(t *testing.T) {
+	p := []model.Process{
+		{
+			PID:         123,
+			Command:     "sshd",
+			StartedAt:   time.Now(),
+			ExeReplaced: true,
+			Package:     &model.PackageInfo{Manager: "dpkg", Owned: true, Name: "openssh-server", Integrity: model.IntegrityModified},
+		},
+	}
+
+	warnings := warningMessages(Warnings(p, Detect(p)))
+	for _, want := range []string{
+		"Executable differs from the digest recorded by package openssh-server (dpkg); it was modified after installation",
+		"Running executable no longer matches the file on disk (replaced after the process started)",
+	} {
+		if !slices.Contains(warnings, want) {
+			t.Errorf("expected %q, got: %v", want, warnings)
+		}
+	}
+
+	for _, w := range Warnings(p, Detect(p)) {
+		if (w.ID == "WITR-PKG-MODIFIED" || w.ID == "WITR-EXE-REPLACED") && w.Severity != model.SeverityHigh {
+			t.Errorf("%s severity = %s, want high", w.ID, w.Severity)
+		}
+	}
+}
+
 func TestEnrichSocketInfo(t *testing.T) {
 	tests := []struct {
 		state           string
@@ -212,3 +250,253 @@ func TestEnrichSocketInfo(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectAllRanksByConfidence(t *testing.T) {
+	tests := []struct {
+		name     string
+		ancestry []model.Process
+		want     string
+		alsoSeen string
+	}{
+		{
+			name: "gunicorn worker is not a shell",
+			ancestry: []model.Process{
+				{PID: 1, Command: "init"},
+				{PID: 500, Command: "python3", Cmdline: "python3 /usr/bin/gunicorn app:app"},
+				{PID: 501, Command: "python3", Cmdline: "python3 /usr/bin/gunicorn app:app"},
+			},
+			want:     "gunicorn",
+			alsoSeen: "python3",
+		},
+		{
+			name: "pm2 beats node",
+			ancestry: []model.Process{
+				{PID: 1, Command: "init"},
+				{PID: 700, Command: "PM2 v5.3.0: God", Cmdline: "PM2 v5.3.0: God Daemon"},
+				{PID: 701, Command: "node", Cmdline: "node /srv/app/index.js"},
+			},
+			want:     "pm2",
+			alsoSeen: "node",
+		},
+		{
+			name: "cron job run through sh",
+			ancestry: []model.Process{
+				{PID: 1, Command: "init"},
+				{PID: 812, Command: "cron"},
+				{PID: 900, Command: "sh", Cmdline: "/bin/sh -c /usr/local/bin/backup"},
+				{PID: 901, Command: "backup"},
+			},
+			want:     "cron",
+			alsoSeen: "sh",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all := DetectAll(tt.ancestry)
+			if len(all) == 0 {
+				t.Fatal("no candidates")
+			}
+			if all[0].Name != tt.want {
+				t.Fatalf("best = %q (%d), want %q; all: %+v", all[0].Name, all[0].Confidence, tt.want, all)
+			}
+			if len(all[0].Evidence) == 0 {
+				t.Errorf("best candidate %q has no evidence", all[0].Name)
+			}
+			found := false
+			for i, c := range all {
+				if i > 0 && c.Confidence > all[i-1].Confidence {
+					t.Errorf("candidates not sorted: %+v", all)
+				}
+				if c.Name == tt.alsoSeen {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected %q among alternatives, got %+v", tt.alsoSeen, all)
+			}
+			if got := Detect(tt.ancestry); got.Name != tt.want {
+				t.Errorf("Detect = %q, want %q", got.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectUnknownWithoutCandidates(t *testing.T) {
+	if got := Detect(nil); got.Type != model.SourceUnknown {
+		t.Fatalf("Detect(nil) = %+v, want unknown", got)
+	}
+}
+
+func TestCustomRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	writeFile(t, path, `
+detectors:
+  - name: acme-launcher
+    match: {comm: acme-run}
+    source:
+      type: supervisor
+      name: acme
+warnings:
+  - name: acme-debug
+    severity: high
+    match: {env: ACME_DEBUG}
+    message: "{{.Command}} runs with ACME_DEBUG={{.Getenv \"ACME_DEBUG\"}}"
+`)
+	set, err := rules.Load([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := customRules
+	customRules = func() *rules.Set { return set }
+	t.Cleanup(func() { customRules = old })
+
+	ancestry := []model.Process{
+		{PID: 1, Command: "init"},
+		{PID: 300, Command: "acme-run"},
+		{PID: 301, Command: "python3", Env: []string{"ACME_DEBUG=2"}},
+	}
+	if got := Detect(ancestry); got.Name != "acme" || got.Type != model.SourceSupervisor {
+		t.Fatalf("Detect = %+v, want acme supervisor", got)
+	}
+	want := "python3 runs with ACME_DEBUG=2"
+	if w := warningMessages(Warnings(ancestry, Detect(ancestry))); !slices.Contains(w, want) {
+		t.Fatalf("expected %q, got %v", want, w)
+	}
+}
+
+func TestWarningsAreStructured(t *testing.T) {
+	p := []model.Process{
+		{
+			PID:            123,
+			Command:        "nginx",
+			User:           "root",
+			StartedAt:      time.Now(),
+			Exe:            "/usr/sbin/nginx",
+			ExeDeleted:     true,
+			BindAddresses:  []string{"0.0.0.0", "127.0.0.1"},
+			ListeningPorts: []int{80, 8080},
+			Env:            []string{"LD_PRELOAD=/tmp/libhack.so"},
+		},
+	}
+
+	byID := map[string]model.Warning{}
+	for _, w := range Warnings(p, Detect(p)) {
+		if w.ID == "" || w.Severity == "" || w.Category == "" || w.Message == "" {
+			t.Errorf("incomplete warning: %+v", w)
+		}
+		byID[w.ID] = w
+	}
+
+	tests := []struct {
+		id       string
+		severity model.Severity
+		evidence string
+	}{
+		{"WITR-ROOT", model.SeverityLow, ""},
+		{"WITR-PUBLIC-BIND", model.SeverityMedium, "listening on 0.0.0.0:80"},
+		{"WITR-DELETED-EXE", model.SeverityHigh, "exe /usr/sbin/nginx"},
+		{"WITR-LD-PRELOAD", model.SeverityHigh, "LD_PRELOAD=/tmp/libhack.so"},
+	}
+	for _, tt := range tests {
+		w, ok := byID[tt.id]
+		if !ok {
+			t.Errorf("missing %s in %v", tt.id, byID)
+			continue
+		}
+		if w.Severity != tt.severity {
+			t.Errorf("%s severity = %s, want %s", tt.id, w.Severity, tt.severity)
+		}
+		if tt.evidence != "" && !slices.Equal(w.Evidence, []string{tt.evidence}) {
+			t.Errorf("%s evidence = %v, want [%s]", tt.id, w.Evidence, tt.evidence)
+		}
+	}
+}
+
+func TestSuppressWarnings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	writeFile(t, path, `
+ignore:
+  - id: WITR-ROOT
+    exe: /usr/sbin/sshd
+  - id: WITR-PUBLIC-BIND
+    unit: nginx
+  - id: WITR-NO-SUPERVISOR
+    source: shell
+`)
+	set, err := rules.Load([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := customRules
+	customRules = func() *rules.Set { return set }
+	t.Cleanup(func() { customRules = old })
+
+	warnings := []model.Warning{
+		{ID: "WITR-ROOT"},
+		{ID: "WITR-PUBLIC-BIND"},
+		{ID: "WITR-NO-SUPERVISOR"},
+		{ID: "WITR-LONG-RUNNING"},
+	}
+	tests := []struct {
+		name string
+		ids  []string
+		src  model.Source
+		proc model.Process
+		want []string
+	}{
+		{
+			name: "exe allowlist",
+			proc: model.Process{Exe: "/usr/sbin/sshd"},
+			src:  model.Source{Type: model.SourceSystemd, Name: "systemd"},
+			want: []string{"WITR-PUBLIC-BIND", "WITR-NO-SUPERVISOR", "WITR-LONG-RUNNING"},
+		},
+		{
+			name: "unit allowlist",
+			proc: model.Process{Exe: "/usr/sbin/nginx", Service: "nginx.service"},
+			src:  model.Source{Type: model.SourceSystemd, Name: "systemd"},
+			want: []string{"WITR-ROOT", "WITR-NO-SUPERVISOR", "WITR-LONG-RUNNING"},
+		},
+		{
+			name: "source allowlist and flag",
+			ids:  []string{"witr-long-running"},
+			proc: model.Process{Exe: "/usr/bin/python3"},
+			src:  model.Source{Type: model.SourceShell, Name: "bash"},
+			want: []string{"WITR-ROOT", "WITR-PUBLIC-BIND"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, suppressed := SuppressWarnings(warnings, tt.ids, tt.src, tt.proc)
+			var got []string
+			for _, w := range kept {
+				got = append(got, w.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("kept %v, want %v", got, tt.want)
+			}
+			if suppressed != len(warnings)-len(tt.want) {
+				t.Errorf("suppressed = %d", suppressed)
+			}
+		})
+	}
+}
+
+func TestZombieWarningNamesLiveParent(t *testing.T) {
+	chain := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 700, PPID: 1, Command: "bash"},
+		{PID: 1500, PPID: 700, Command: "make", Exited: true},
+		{PID: 990, PPID: 1500, Command: "cc", Health: "zombie"},
+	}
+	want := "Process is a zombie (defunct); parent pid 1500 has not reaped it"
+	if !slices.Contains(warningMessages(Warnings(chain, Detect(chain))), want) {
+		t.Errorf("adopted zombie: %v", warningMessages(Warnings(chain, Detect(chain))))
+	}
+
+	chain[3].PPID = 700
+	want = "Process is a zombie (defunct); parent bash (pid 700) has not reaped it"
+	if !slices.Contains(warningMessages(Warnings(chain, Detect(chain))), want) {
+		t.Errorf("zombie under exited ancestor: %v", warningMessages(Warnings(chain, Detect(chain))))
+	}
+}
@@ -39,6 +39,7 @@ func detectInit(ancestry []model.Process) *model.Source {
 				"pid":  "1",
 				"comm": root.Command,
 			},
+			Evidence: []string{"ancestry starts at pid 1 (" + root.Command + ") with no shell in between"},
 		}
 	}
 
@@ -0,0 +1,149 @@
+package source
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// kernelThread describes a family of kernel threads by comm pattern. The
+// family is the short name reported as the source; explain receives the
+// pattern's submatches.
+type kernelThread struct {
+	pattern *regexp.Regexp
+	family  string
+	explain func(m []string) string
+}
+
+func fixed(s string) func([]string) string {
+	return func([]string) string { return s }
+}
+
+// kernelThreads is checked in order, so specific patterns come before
+// generic prefixes.
+var kernelThreads = []kernelThread{
+	{regexp.MustCompile(`^kthreadd$`), "kthreadd", fixed("Kernel thread daemon; creates and parents every other kernel thread")},
+	{regexp.MustCompile(`^kworker/R-(.+)$`), "kworker", func(m []string) string {
+		return "Rescuer worker for the " + m[1] + " workqueue, kept in reserve so queued work progresses under memory pressure"
+	}},
+	{regexp.MustCompile(`^kworker/(u?)(\d+):\d+(H?)(?:-(.+))?$`), "kworker", explainKworker},
+	{regexp.MustCompile(`^ksoftirqd/(\d+)$`), "ksoftirqd", func(m []string) string {
+		return "Processes deferred software interrupts (network, timers, block I/O) on CPU " + m[1] + " when they arrive faster than they can be handled inline"
+	}},
+	{regexp.MustCompile(`^migration/(\d+)$`), "migration", func(m []string) string {
+		return "Moves tasks off CPU " + m[1] + " for scheduler load balancing and CPU hotplug"
+	}},
+	{regexp.MustCompile(`^(?:rcu_|rcuo|rcub/)`), "rcu", fixed("Read-copy-update (RCU) grace period and callback processing")},
+	{regexp.MustCompile(`^cpuhp/(\d+)$`), "cpuhp", func(m []string) string {
+		return "Runs CPU hotplug state transitions for CPU " + m[1]
+	}},
+	{regexp.MustCompile(`^(?:idle_inject|watchdog)/(\d+)$`), "watchdog", func(m []string) string {
+		return "Per-CPU soft lockup watchdog and idle injection for CPU " + m[1]
+	}},
+	{regexp.MustCompile(`^kswapd(\d+)$`), "kswapd", func(m []string) string {
+		return "Reclaims memory on NUMA node " + m[1] + " when free pages run low; sustained CPU use means memory pressure"
+	}},
+	{regexp.MustCompile(`^kcompactd(\d+)$`), "kcompactd", func(m []string) string {
+		return "Compacts memory on NUMA node " + m[1] + " to create contiguous free pages"
+	}},
+	{regexp.MustCompile(`^khugepaged$`), "khugepaged", fixed("Collapses small pages into transparent huge pages")},
+	{regexp.MustCompile(`^oom_reaper$`), "oom_reaper", fixed("Frees memory of processes killed by the OOM killer")},
+	{regexp.MustCompile(`^kauditd$`), "kauditd", fixed("Delivers audit records from the kernel to auditd")},
+	{regexp.MustCompile(`^jbd2/(.+)-\d+$`), "jbd2", func(m []string) string {
+		return "ext4 journal commit thread for " + m[1] + "; heavy CPU or I/O here tracks write load on that filesystem"
+	}},
+	{regexp.MustCompile(`^(?:ext4-rsv-conversion|xfs)`), "filesystem", fixed("Filesystem background work (ext4/XFS log and metadata writeback)")},
+	{regexp.MustCompile(`^nvme`), "nvme", fixed("NVMe driver workqueue handling controller resets, timeouts and completions")},
+	{regexp.MustCompile(`^(md\d+)_([a-z0-9]+)$`), "md", func(m []string) string {
+		return "Linux software RAID (md) " + m[2] + " thread for /dev/" + m[1]
+	}},
+	{regexp.MustCompile(`^md(?:_bitmap)?$`), "md", fixed("Linux software RAID (md) workqueue")},
+	{regexp.MustCompile(`^(?:z_|txg_|spl_|arc_|dbu_|dbuf_|l2arc_|zthr_)`), "zfs", fixed("OpenZFS worker (I/O pipeline, transaction groups, ARC cache management)")},
+	{regexp.MustCompile(`^wg-crypt-(.+)$`), "wireguard", func(m []string) string {
+		return "WireGuard encryption/decryption worker for interface " + m[1]
+	}},
+	{regexp.MustCompile(`^(?:scsi_eh|scsi_tmf)_(\d+)$`), "scsi", func(m []string) string {
+		return "SCSI error handling for host " + m[1]
+	}},
+	{regexp.MustCompile(`^irq/(\d+)-(.+)$`), "irq", func(m []string) string {
+		return "Threaded interrupt handler for IRQ " + m[1] + " (" + m[2] + ")"
+	}},
+	{regexp.MustCompile(`^(?:kblockd|blkcg_punt_bio|writeback|kthrotld|kintegrityd|ata_sff|kdmflush.*)$`), "block", fixed("Block layer, device-mapper and dirty page writeback workqueue")},
+}
+
+func explainKworker(m []string) string {
+	s := "Workqueue worker running deferred kernel work"
+	if m[1] == "u" {
+		s += " for unbound pool " + m[2]
+	} else {
+		s += " pinned to CPU " + m[2]
+	}
+	if m[3] == "H" {
+		s += " at high priority"
+	}
+	if m[4] != "" {
+		s += "; currently serving " + m[4]
+	}
+	return s
+}
+
+// KernelThreadInfo returns the family and explanation for a kernel thread
+// name, or ok=false when the name is not in the catalog.
+func KernelThreadInfo(name string) (family, explanation string, ok bool) {
+	for _, t := range kernelThreads {
+		if m := t.pattern.FindStringSubmatch(name); m != nil {
+			return t.family, t.explain(m), true
+		}
+	}
+	return "", "", false
+}
+
+// detectKernel attributes kernel threads to the kernel itself. Nothing in
+// userland started them, so no other detector applies.
+func detectKernel(ancestry []model.Process) *model.Source {
+	if len(ancestry) == 0 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+	if !target.KernelThread {
+		return nil
+	}
+
+	evidence := []string{"PF_KTHREAD flag set in /proc/" + strconv.Itoa(target.PID) + "/stat"}
+	if target.PPID == 2 {
+		evidence = append(evidence, "parent is kthreadd (pid 2)")
+	}
+	if target.Cmdline == "" {
+		evidence = append(evidence, "empty command line and no executable")
+	}
+
+	src := &model.Source{
+		Type:        model.SourceKernel,
+		Name:        "kernel",
+		Description: "Kernel thread started by the kernel, not by any userland process",
+		Confidence:  100,
+		Evidence:    evidence,
+	}
+	if family, explanation, ok := KernelThreadInfo(target.Command); ok {
+		src.Name = family
+		src.Description = explanation
+	}
+	return src
+}
+
+// kernelThreadWarnings keeps the warnings that still mean something for a
+// kernel thread. Ownership, supervision, working directory and executable
+// checks are userland concepts and would only be noise.
+func kernelThreadWarnings(p model.Process) []model.Warning {
+	var w []model.Warning
+	if p.Health == "high-cpu" {
+		w = append(w, model.Warning{
+			ID:       "WITR-HIGH-CPU",
+			Severity: model.SeverityLow,
+			Category: model.CategoryResources,
+			Message:  "Kernel thread is using high CPU (>2h total); look for the workload driving it",
+		})
+	}
+	return append(w, customWarnings(p)...)
+}
@@ -0,0 +1,66 @@
+package source
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestKernelThreadInfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		family string
+		want   string
+	}{
+		{"kworker/u8:2-events_unbound", "kworker", "unbound pool 8; currently serving events_unbound"},
+		{"kworker/3:1H-kblockd", "kworker", "pinned to CPU 3 at high priority; currently serving kblockd"},
+		{"kworker/R-rcu_gp", "kworker", "Rescuer worker for the rcu_gp workqueue"},
+		{"ksoftirqd/0", "ksoftirqd", "on CPU 0"},
+		{"jbd2/nvme0n1p2-8", "jbd2", "ext4 journal commit thread for nvme0n1p2"},
+		{"nvme-wq", "nvme", "NVMe driver"},
+		{"md127_raid1", "md", "raid1 thread for /dev/md127"},
+		{"txg_sync", "zfs", "OpenZFS"},
+		{"wg-crypt-wg0", "wireguard", "interface wg0"},
+		{"irq/125-iwlwifi", "irq", "IRQ 125 (iwlwifi)"},
+	}
+	for _, tt := range tests {
+		family, explanation, ok := KernelThreadInfo(tt.name)
+		if !ok || family != tt.family || !strings.Contains(explanation, tt.want) {
+			t.Errorf("KernelThreadInfo(%q) = %q, %q, %v; want %q containing %q", tt.name, family, explanation, ok, tt.family, tt.want)
+		}
+	}
+
+	if _, _, ok := KernelThreadInfo("my_custom_thread"); ok {
+		t.Error("unknown thread name should not match")
+	}
+}
+
+func TestDetectKernelThread(t *testing.T) {
+	chain := []model.Process{
+		{PID: 2, Command: "kthreadd", User: "root", KernelThread: true},
+		{PID: 412, PPID: 2, Command: "jbd2/sda1-8", User: "root", WorkingDir: "/", KernelThread: true},
+	}
+
+	src := Detect(chain)
+	if src.Type != model.SourceKernel || src.Name != "jbd2" {
+		t.Fatalf("Detect = %s/%s, want kernel/jbd2", src.Type, src.Name)
+	}
+	if len(src.Evidence) != 3 {
+		t.Errorf("Evidence = %v", src.Evidence)
+	}
+
+	// Root, no-supervisor and cwd warnings make no sense for kernel threads
+	if got := Warnings(chain, Detect(chain)); len(got) != 0 {
+		t.Errorf("Warnings = %v, want none", warningMessages(got))
+	}
+	chain[1].Health = "high-cpu"
+	if got := Warnings(chain, Detect(chain)); len(got) != 1 || got[0].ID != "WITR-HIGH-CPU" {
+		t.Errorf("Warnings = %v, want only WITR-HIGH-CPU", warningMessages(got))
+	}
+
+	unknown := Detect([]model.Process{{PID: 900, PPID: 2, Command: "acme_poll", KernelThread: true}})
+	if unknown.Type != model.SourceKernel || unknown.Name != "kernel" {
+		t.Errorf("uncatalogued thread = %s/%s, want kernel/kernel", unknown.Type, unknown.Name)
+	}
+}
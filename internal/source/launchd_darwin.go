@@ -34,8 +34,10 @@ func detectLaunchd(ancestry []model.Process) *model.Source {
 	if err != nil {
 		// Fall back to basic launchd detection
 		return &model.Source{
-			Type: model.SourceLaunchd,
-			Name: "launchd",
+			Type:       model.SourceLaunchd,
+			Name:       "launchd",
+			Confidence: 40,
+			Evidence:   []string{"pid 1 is launchd"},
 		}
 	}
 
@@ -45,6 +47,16 @@ func detectLaunchd(ancestry []model.Process) *model.Source {
 		Name:        info.Label,
 		Description: info.Comment,
 		Details:     make(map[string]string),
+		Confidence:  85,
+		Evidence:    []string{"pid 1 is launchd", "launchd reports job " + info.Label + " for pid " + itoa(target.PID)},
+	}
+	// Apps started from a terminal still belong to the terminal's launchd job
+	for _, p := range ancestry[:len(ancestry)-1] {
+		if shells[p.Command] {
+			source.Confidence = 50
+			source.Evidence = append(source.Evidence, "ancestor "+itoa(p.PID)+" is shell "+p.Command)
+			break
+		}
 	}
 
 	// Add domain description (Launch Agent vs Launch Daemon)
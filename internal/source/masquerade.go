@@ -0,0 +1,244 @@
+package source
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// commLen is the kernel's TASK_COMM_LEN minus the terminator; longer names
+// are truncated in comm.
+const commLen = 15
+
+// kernelThreadPrefixes are comm names of common kernel threads. A process
+// with an executable cannot be a kernel thread.
+var kernelThreadPrefixes = []string{
+	"kworker", "ksoftirqd", "kthreadd", "kswapd", "migration", "rcu_",
+	"watchdog", "kcompactd", "khugepaged", "kblockd", "kauditd", "jbd2",
+	"irq/", "cpuhp", "oom_reaper", "writeback", "kdevtmpfs",
+}
+
+// impersonatedDaemons are names malware commonly borrows. Their real
+// executables carry the same name.
+var impersonatedDaemons = map[string]bool{
+	"sshd": true, "cron": true, "crond": true, "dbus-daemon": true,
+	"rsyslogd": true, "agetty": true, "systemd-journald": true,
+	"systemd-logind": true, "polkitd": true, "nginx": true, "httpd": true,
+}
+
+// userlandBracketTitles are titles userland programs legitimately give
+// themselves in kernel-thread style brackets, e.g.
+// "[lxc monitor] /var/lib/lxc web".
+var userlandBracketTitles = []string{"lxc monitor"}
+
+// tempExecDirs are world-writable locations that should not hold long-lived binaries.
+var tempExecDirs = []string{"/tmp", "/var/tmp", "/dev/shm"}
+
+// executableWarnings reports masquerading and fileless execution.
+func executableWarnings(p model.Process) []model.Warning {
+	if p.Exe == "" {
+		return nil
+	}
+	var w []model.Warning
+
+	// comm is a Linux notion; other platforms report full process names
+	if runtime.GOOS == "linux" {
+		if warning, ok := masqueradeWarning(p); ok {
+			w = append(w, warning)
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(p.Exe, "/memfd:"):
+		w = append(w, model.Warning{
+			ID:       "WITR-FILELESS",
+			Severity: model.SeverityHigh,
+			Category: model.CategorySecurity,
+			Message:  "Process is running from an in-memory file (memfd); there is no binary on disk",
+			Evidence: []string{"exe " + p.Exe},
+		})
+	case inTempDir(p.Exe) != "":
+		w = append(w, model.Warning{
+			ID:       "WITR-TEMP-EXE",
+			Severity: model.SeverityHigh,
+			Category: model.CategorySecurity,
+			Message:  "Process is running from a temporary directory: " + inTempDir(p.Exe),
+			Evidence: []string{"exe " + p.Exe},
+		})
+	}
+
+	if sec := p.Security; sec != nil {
+		if sec.ExeWorldWritable || sec.ExeDirWorldWritable {
+			var evidence []string
+			if sec.ExeWorldWritable {
+				evidence = append(evidence, "exe "+p.Exe+" has mode "+sec.ExeMode)
+			}
+			if sec.ExeDirWorldWritable {
+				evidence = append(evidence, "directory "+filepath.Dir(p.Exe)+" is world-writable without the sticky bit")
+			}
+			w = append(w, model.Warning{
+				ID:       "WITR-WRITABLE-EXE",
+				Severity: model.SeverityHigh,
+				Category: model.CategorySecurity,
+				Message:  "Executable can be replaced by any user (world-writable file or directory)",
+				Evidence: evidence,
+			})
+		}
+		if sec.NoexecMount != "" {
+			w = append(w, model.Warning{
+				ID:       "WITR-NOEXEC-EXE",
+				Severity: model.SeverityHigh,
+				Category: model.CategorySecurity,
+				Message:  "Executable lives on a noexec mount; it was likely started through a loader trick",
+				Evidence: []string{"exe " + p.Exe, "mount " + sec.NoexecMount + " is mounted noexec"},
+			})
+		}
+	}
+
+	return w
+}
+
+// masqueradeWarning flags a comm that matches neither the executable nor
+// argv[0]. Programs rename their threads legitimately (browsers, JVMs), so a
+// plain mismatch is informational. Posing as a kernel thread, or as a system
+// daemon from an executable of another name, is not.
+func masqueradeWarning(p model.Process) (model.Warning, bool) {
+	comm := p.Command
+	exeName := filepath.Base(p.Exe)
+	argv0 := argv0Name(p.Cmdline)
+
+	evidence := []string{"comm " + comm, "exe " + p.Exe}
+	if argv0 != "" {
+		evidence = append(evidence, "argv[0] "+argv0)
+	}
+	warning := model.Warning{
+		ID:       "WITR-MASQUERADE",
+		Severity: model.SeverityHigh,
+		Category: model.CategorySecurity,
+		Evidence: evidence,
+	}
+
+	exeMatch := commMatches(comm, exeName)
+	switch {
+	case !p.KernelThread && posesAsKernelThread(p.Cmdline, comm, exeName, exeMatch):
+		warning.Message = "Process poses as a kernel thread but runs an executable"
+		return warning, true
+	case strings.HasPrefix(p.Exe, "/memfd:"):
+		// Reported as fileless; the memfd name is arbitrary
+		return model.Warning{}, false
+	case !exeMatch && (impersonatedDaemons[comm] || impersonatedDaemons[argv0]):
+		warning.Message = "Process name does not match its executable (posing as a system daemon)"
+		return warning, true
+	case !exeMatch && !commMatches(comm, argv0) && !commMatches(comm, scriptName(p, exeName)):
+		warning.Severity = model.SeverityInfo
+		warning.Message = "Process name does not match its executable or argv[0]"
+		return warning, true
+	}
+	return model.Warning{}, false
+}
+
+// scriptName returns the base name of the script an interpreter runs. The
+// kernel names a shebang script's process after the script (backup.sh)
+// while its executable and argv[0] are the interpreter.
+func scriptName(p model.Process, exeName string) string {
+	if !IsShellOrInterpreter(exeName) {
+		return ""
+	}
+	if ep := p.Entrypoint; ep != nil && ep.Kind == "script" {
+		return filepath.Base(ep.Target)
+	}
+	fields := strings.Fields(p.Cmdline)
+	if len(fields) < 2 {
+		return ""
+	}
+	for _, arg := range fields[1:] {
+		if !strings.HasPrefix(arg, "-") {
+			return filepath.Base(arg)
+		}
+	}
+	return ""
+}
+
+// posesAsKernelThread reports a process titled like a kernel thread,
+// "[name]" or a kworker-style comm, whose executable is something else.
+func posesAsKernelThread(cmdline, comm, exeName string, exeMatch bool) bool {
+	if isKernelThreadName(comm) && !exeMatch {
+		return true
+	}
+	title, ok := bracketTitle(cmdline)
+	if !ok {
+		return false
+	}
+	for _, allowed := range userlandBracketTitles {
+		if title == allowed {
+			return false
+		}
+	}
+	name, _, _ := strings.Cut(title, " ")
+	if isKernelThreadName(name) {
+		return true
+	}
+	// "[worker] ..." from /usr/bin/worker, or with comm worker, names
+	// itself honestly
+	return !commMatches(name, exeName) && !commMatches(comm, name)
+}
+
+// bracketTitle returns the text of a leading "[...]" in cmdline.
+func bracketTitle(cmdline string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(cmdline), "[")
+	if !ok {
+		return "", false
+	}
+	title, _, ok := strings.Cut(rest, "]")
+	return title, ok
+}
+
+// argv0Name returns the base name of argv[0], ignoring the login-shell dash,
+// the "name: title" form daemons like sshd and nginx use and "[name] ..."
+// titles.
+func argv0Name(cmdline string) string {
+	if title, ok := bracketTitle(cmdline); ok {
+		cmdline = title
+	}
+	fields := strings.Fields(cmdline)
+	if len(fields) == 0 {
+		return ""
+	}
+	name := strings.TrimPrefix(filepath.Base(fields[0]), "-")
+	return strings.TrimSuffix(name, ":")
+}
+
+// commMatches compares comm to a full name, allowing for kernel truncation
+// and titles such as "tmux: server" that keep the program name in front.
+func commMatches(comm, name string) bool {
+	if name == "" {
+		return false
+	}
+	if strings.HasPrefix(comm, name+":") || strings.HasPrefix(comm, name+" ") {
+		return true
+	}
+	if len(name) > commLen {
+		name = name[:commLen]
+	}
+	return comm == name
+}
+
+func isKernelThreadName(comm string) bool {
+	for _, prefix := range kernelThreadPrefixes {
+		if strings.HasPrefix(comm, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func inTempDir(path string) string {
+	for _, dir := range tempExecDirs {
+		if strings.HasPrefix(path, dir+"/") {
+			return dir
+		}
+	}
+	return ""
+}
@@ -0,0 +1,127 @@
+package source
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestExecutableWarnings(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("comm checks are Linux-only")
+	}
+	tests := []struct {
+		name     string
+		proc     model.Process
+		want     []string
+		severity model.Severity
+	}{
+		{
+			name: "matching names",
+			proc: model.Process{Command: "nginx", Exe: "/usr/sbin/nginx", Cmdline: "nginx: worker process"},
+		},
+		{
+			name: "interpreter symlink matches argv[0]",
+			proc: model.Process{Command: "python3", Exe: "/usr/bin/python3.12", Cmdline: "python3 app.py"},
+		},
+		{
+			name: "truncated comm",
+			proc: model.Process{Command: "systemd-resolve", Exe: "/usr/lib/systemd/systemd-resolved", Cmdline: "/usr/lib/systemd/systemd-resolved"},
+		},
+		{
+			name: "titled comm",
+			proc: model.Process{Command: "tmux: server", Exe: "/usr/bin/tmux", Cmdline: "tmux new -d"},
+		},
+		{
+			name: "login shell",
+			proc: model.Process{Command: "bash", Exe: "/usr/bin/bash", Cmdline: "-bash"},
+		},
+		{
+			name: "shebang shell script",
+			proc: model.Process{Command: "backup.sh", Exe: "/usr/bin/bash", Cmdline: "/bin/bash /opt/backup.sh"},
+		},
+		{
+			name: "shebang python tool",
+			proc: model.Process{Command: "certbot", Exe: "/usr/bin/python3.12", Cmdline: "/usr/bin/python3 -s /usr/bin/certbot renew", Entrypoint: &model.Entrypoint{Kind: "script", Target: "/usr/bin/certbot"}},
+		},
+		{
+			name:     "interpreter comm matching neither script nor argv[0]",
+			proc:     model.Process{Command: "kinsing", Exe: "/usr/bin/bash", Cmdline: "/bin/bash /opt/backup.sh"},
+			want:     []string{"WITR-MASQUERADE"},
+			severity: model.SeverityInfo,
+		},
+		{
+			name: "lxc monitor title",
+			proc: model.Process{Command: "lxc-start", Exe: "/usr/bin/lxc-start", Cmdline: "[lxc monitor] /var/lib/lxc web"},
+		},
+		{
+			name: "bracketed title naming its own program",
+			proc: model.Process{Command: "celery", Exe: "/usr/bin/python3.12", Cmdline: "[celery] worker"},
+		},
+		{
+			name:     "bracketed title of another program",
+			proc:     model.Process{Command: "x", Exe: "/home/bob/.cache/x", Cmdline: "[migration/3]"},
+			want:     []string{"WITR-MASQUERADE"},
+			severity: model.SeverityHigh,
+		},
+		{
+			name:     "fake kernel thread",
+			proc:     model.Process{Command: "kworker/0:1", Exe: "/home/bob/.cache/x", Cmdline: "[kworker/0:1]"},
+			want:     []string{"WITR-MASQUERADE"},
+			severity: model.SeverityHigh,
+		},
+		{
+			name:     "fake sshd",
+			proc:     model.Process{Command: "sshd", Exe: "/usr/local/bin/miner", Cmdline: "sshd"},
+			want:     []string{"WITR-MASQUERADE"},
+			severity: model.SeverityHigh,
+		},
+		{
+			name:     "renamed thread",
+			proc:     model.Process{Command: "Isolated Web Co", Exe: "/usr/lib/firefox/firefox", Cmdline: "/usr/lib/firefox/firefox -contentproc"},
+			want:     []string{"WITR-MASQUERADE"},
+			severity: model.SeverityInfo,
+		},
+		{
+			name:     "memfd",
+			proc:     model.Process{Command: "x", Exe: "/memfd:x", Cmdline: "x", ExeDeleted: true},
+			want:     []string{"WITR-FILELESS"},
+			severity: model.SeverityHigh,
+		},
+		{
+			name:     "shared memory",
+			proc:     model.Process{Command: "run", Exe: "/dev/shm/run", Cmdline: "./run"},
+			want:     []string{"WITR-TEMP-EXE"},
+			severity: model.SeverityHigh,
+		},
+		{
+			name: "world-writable directory and noexec mount",
+			proc: model.Process{Command: "agent", Exe: "/opt/shared/agent", Cmdline: "agent", Security: &model.SecurityInfo{
+				ExeDirWorldWritable: true,
+				NoexecMount:         "/opt/shared",
+			}},
+			want:     []string{"WITR-WRITABLE-EXE", "WITR-NOEXEC-EXE"},
+			severity: model.SeverityHigh,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, w := range executableWarnings(tt.proc) {
+				ids = append(ids, w.ID)
+				if w.Severity != tt.severity {
+					t.Errorf("%s severity = %s, want %s", w.ID, w.Severity, tt.severity)
+				}
+				if len(w.Evidence) == 0 {
+					t.Errorf("%s has no evidence", w.ID)
+				}
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Fatalf("warnings = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package source
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// tmuxPane holds what tmux reports about the pane a process runs in.
+type tmuxPane struct {
+	Session    string
+	WindowIdx  string
+	WindowName string
+	PaneIdx    string
+}
+
+// tmuxQuery asks the tmux server behind socket about a pane.
+// It is a variable so tests can run without a tmux binary.
+var tmuxQuery = queryTmuxPane
+
+func detectMultiplexer(ancestry []model.Process) *model.Source {
+	// Scan from the target backwards so the closest server wins when
+	// multiplexers are nested (e.g. screen inside tmux).
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		p := ancestry[i]
+		switch {
+		case isTmuxServer(p, ancestry[i+1:]):
+			src := tmuxSource(p, ancestry[i+1:])
+			src.Evidence = []string{"ancestor " + itoa(p.PID) + " is the tmux server"}
+			return src
+		case isScreenServer(p):
+			src := screenSource(p, ancestry[i+1:])
+			src.Evidence = []string{"ancestor " + itoa(p.PID) + " is the screen server"}
+			return src
+		}
+	}
+	return nil
+}
+
+// tmux renames its server process to "tmux: server"; clients such as
+// "tmux attach" keep their command line. Where the title is not visible
+// (macOS), the server is the pid named in its panes' TMUX variable.
+func isTmuxServer(p model.Process, below []model.Process) bool {
+	if p.Command == "tmux: server" || strings.HasPrefix(p.Cmdline, "tmux: server") {
+		return true
+	}
+	if filepath.Base(p.Command) != "tmux" {
+		return false
+	}
+	if child := closestWithEnv(below, "TMUX"); child != nil {
+		return tmuxServerPID(envValue(child.Env, "TMUX")) == p.PID
+	}
+	return false
+}
+
+// The screen server runs as "SCREEN"; on Linux comm is usually "screen".
+func isScreenServer(p model.Process) bool {
+	return strings.EqualFold(p.Command, "screen")
+}
+
+func tmuxSource(server model.Process, below []model.Process) *model.Source {
+	src := &model.Source{
+		Type:    model.SourceMultiplexer,
+		Name:    "tmux",
+		Details: map[string]string{},
+	}
+
+	socket, sessionID, pane := "", "", ""
+	if p := closestWithEnv(below, "TMUX"); p != nil {
+		socket, sessionID = parseTmuxEnv(envValue(p.Env, "TMUX"))
+		pane = envValue(p.Env, "TMUX_PANE")
+	}
+	if socket == "" {
+		socket = tmuxSocketFromCmdline(server.Cmdline)
+	}
+
+	session := ""
+	if socket != "" && pane != "" {
+		if info, ok := tmuxQuery(socket, pane); ok {
+			session = info.Session
+			if info.WindowIdx != "" {
+				window := info.WindowIdx
+				if info.WindowName != "" {
+					window += " (" + info.WindowName + ")"
+				}
+				src.Details["window"] = window
+			}
+			if info.PaneIdx != "" {
+				src.Details["pane"] = info.PaneIdx + " (" + pane + ")"
+			}
+		}
+	}
+	if session == "" && sessionID != "" {
+		session = "$" + sessionID
+	}
+	if _, ok := src.Details["pane"]; !ok && pane != "" {
+		src.Details["pane"] = pane
+	}
+
+	if session != "" {
+		src.Details["session"] = session
+		src.Description = "tmux session " + strconv.Quote(session)
+	}
+	if socket != "" {
+		src.Details["socket"] = socket
+	}
+
+	owner := multiplexerOwner(server, socket, "tmux-")
+	if owner != "" {
+		src.Details["owner"] = owner
+	}
+
+	if session != "" {
+		target := session
+		if pane != "" {
+			target = pane
+		}
+		attach := "tmux attach -t " + target
+		if socket != "" {
+			attach = "tmux -S " + socket + " attach -t " + target
+		}
+		src.Details["attach"] = attach
+	}
+
+	return src
+}
+
+func screenSource(server model.Process, below []model.Process) *model.Source {
+	src := &model.Source{
+		Type:    model.SourceMultiplexer,
+		Name:    "screen",
+		Details: map[string]string{},
+	}
+
+	sty, window := "", ""
+	if p := closestWithEnv(below, "STY"); p != nil {
+		sty = envValue(p.Env, "STY")
+		window = envValue(p.Env, "WINDOW")
+	}
+
+	if sty != "" {
+		// STY is "<server pid>.<session name>"
+		name := sty
+		if _, rest, ok := strings.Cut(sty, "."); ok && rest != "" {
+			name = rest
+		}
+		src.Details["session"] = name
+		src.Description = "screen session " + strconv.Quote(name)
+	}
+	if window != "" {
+		src.Details["window"] = window
+	}
+
+	owner := multiplexerOwner(server, "", "")
+	if owner != "" {
+		src.Details["owner"] = owner
+	}
+
+	if sty != "" {
+		attach := "screen -r " + sty
+		if window != "" {
+			attach += " -p " + window
+		}
+		src.Details["attach"] = attach
+	}
+
+	return src
+}
+
+// closestWithEnv returns the process nearest to the target that carries key in its environment.
+func closestWithEnv(procs []model.Process, key string) *model.Process {
+	for i := len(procs) - 1; i >= 0; i-- {
+		if envValue(procs[i].Env, key) != "" {
+			return &procs[i]
+		}
+	}
+	return nil
+}
+
+func envValue(env []string, key string) string {
+	for _, entry := range env {
+		if k, v, ok := strings.Cut(entry, "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseTmuxEnv splits a TMUX value ("<socket>,<server pid>,<session id>")
+// into the socket path and session id.
+func parseTmuxEnv(value string) (socket, sessionID string) {
+	if value == "" {
+		return "", ""
+	}
+	parts := strings.Split(value, ",")
+	if len(parts) < 3 {
+		return parts[0], ""
+	}
+	// The socket path itself may contain commas, so count from the end.
+	return strings.Join(parts[:len(parts)-2], ","), parts[len(parts)-1]
+}
+
+// tmuxServerPID returns the server pid of a TMUX value, or 0.
+func tmuxServerPID(value string) int {
+	parts := strings.Split(value, ",")
+	if len(parts) < 3 {
+		return 0
+	}
+	pid, _ := strconv.Atoi(parts[len(parts)-2])
+	return pid
+}
+
+// tmuxSocketFromCmdline extracts the socket from a "tmux: server (<socket>)" title.
+func tmuxSocketFromCmdline(cmdline string) string {
+	open := strings.Index(cmdline, "(")
+	close := strings.LastIndex(cmdline, ")")
+	if open == -1 || close <= open {
+		return ""
+	}
+	return cmdline[open+1 : close]
+}
+
+// multiplexerOwner prefers the server's user and falls back to the UID
+// encoded in the socket directory (/tmp/tmux-<uid>/default).
+func multiplexerOwner(server model.Process, socket, dirPrefix string) string {
+	if server.User != "" && server.User != "unknown" {
+		return server.User
+	}
+	if socket == "" || dirPrefix == "" {
+		return ""
+	}
+	dir := filepath.Base(filepath.Dir(socket))
+	if uid, ok := strings.CutPrefix(dir, dirPrefix); ok && uid != "" {
+		return "uid " + uid
+	}
+	return ""
+}
+
+func queryTmuxPane(socket, pane string) (tmuxPane, bool) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return tmuxPane{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	format := "#{session_name}\t#{window_index}\t#{window_name}\t#{pane_index}"
+	out, err := exec.CommandContext(ctx, "tmux", "-S", socket, "display-message", "-p", "-t", pane, format).Output()
+	if err != nil {
+		return tmuxPane{}, false
+	}
+
+	parts := strings.Split(strings.TrimSpace(string(out)), "\t")
+	if len(parts) != 4 || parts[0] == "" {
+		return tmuxPane{}, false
+	}
+	return tmuxPane{
+		Session:    parts[0],
+		WindowIdx:  parts[1],
+		WindowName: parts[2],
+		PaneIdx:    parts[3],
+	}, true
+}
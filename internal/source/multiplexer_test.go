@@ -0,0 +1,156 @@
+package source
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestParseTmuxEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		wantSocket  string
+		wantSession string
+	}{
+		{"default socket", "/tmp/tmux-1000/default,4242,3", "/tmp/tmux-1000/default", "3"},
+		{"comma in socket", "/tmp/a,b/sock,4242,0", "/tmp/a,b/sock", "0"},
+		{"socket only", "/tmp/tmux-1000/default", "/tmp/tmux-1000/default", ""},
+		{"empty", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			socket, session := parseTmuxEnv(tt.value)
+			if socket != tt.wantSocket || session != tt.wantSession {
+				t.Fatalf("parseTmuxEnv(%q) = (%q, %q), want (%q, %q)", tt.value, socket, session, tt.wantSocket, tt.wantSession)
+			}
+		})
+	}
+}
+
+func TestDetectMultiplexerTmux(t *testing.T) {
+	orig := tmuxQuery
+	defer func() { tmuxQuery = orig }()
+	tmuxQuery = func(socket, pane string) (tmuxPane, bool) {
+		if socket != "/tmp/tmux-1000/default" || pane != "%7" {
+			t.Fatalf("unexpected query socket=%q pane=%q", socket, pane)
+		}
+		return tmuxPane{Session: "deploy", WindowIdx: "2", WindowName: "logs", PaneIdx: "1"}, true
+	}
+
+	ancestry := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 900, Command: "tmux: server", Cmdline: "tmux: server (/tmp/tmux-1000/default)", User: "alice"},
+		{PID: 901, Command: "bash", Env: []string{"TMUX=/tmp/tmux-1000/default,900,4", "TMUX_PANE=%7"}},
+		{PID: 950, Command: "python3", Env: []string{"TMUX=/tmp/tmux-1000/default,900,4", "TMUX_PANE=%7"}},
+	}
+
+	src := detectMultiplexer(ancestry)
+	if src == nil {
+		t.Fatal("expected tmux source, got nil")
+	}
+	if src.Type != model.SourceMultiplexer || src.Name != "tmux" {
+		t.Fatalf("got %s/%s, want multiplexer/tmux", src.Type, src.Name)
+	}
+
+	want := map[string]string{
+		"session": "deploy",
+		"window":  "2 (logs)",
+		"pane":    "1 (%7)",
+		"socket":  "/tmp/tmux-1000/default",
+		"owner":   "alice",
+		"attach":  "tmux -S /tmp/tmux-1000/default attach -t %7",
+	}
+	for k, v := range want {
+		if src.Details[k] != v {
+			t.Errorf("Details[%q] = %q, want %q", k, src.Details[k], v)
+		}
+	}
+}
+
+func TestDetectMultiplexerTmuxWithoutCLI(t *testing.T) {
+	orig := tmuxQuery
+	defer func() { tmuxQuery = orig }()
+	tmuxQuery = func(string, string) (tmuxPane, bool) { return tmuxPane{}, false }
+
+	ancestry := []model.Process{
+		{PID: 900, Command: "tmux: server", User: "unknown"},
+		{PID: 901, Command: "bash", Env: []string{"TMUX=/tmp/tmux-1001/work,900,2", "TMUX_PANE=%3"}},
+	}
+
+	src := detectMultiplexer(ancestry)
+	if src == nil {
+		t.Fatal("expected tmux source, got nil")
+	}
+	if src.Details["session"] != "$2" {
+		t.Errorf("session = %q, want %q", src.Details["session"], "$2")
+	}
+	if src.Details["owner"] != "uid 1001" {
+		t.Errorf("owner = %q, want %q", src.Details["owner"], "uid 1001")
+	}
+	if src.Details["pane"] != "%3" {
+		t.Errorf("pane = %q, want %q", src.Details["pane"], "%3")
+	}
+}
+
+func TestDetectMultiplexerTmuxClient(t *testing.T) {
+	orig := tmuxQuery
+	defer func() { tmuxQuery = orig }()
+	tmuxQuery = func(string, string) (tmuxPane, bool) { return tmuxPane{}, false }
+
+	// A client is not the session server, even with a tmux session around it
+	client := []model.Process{
+		{PID: 1, Command: "init"},
+		{PID: 500, Command: "tmux", Cmdline: "tmux attach -t work"},
+		{PID: 501, Command: "sh", Env: []string{"TMUX=/tmp/tmux-1000/default,400,1"}},
+	}
+	if src := detectMultiplexer(client); src != nil {
+		t.Fatalf("tmux client reported as server: %+v", src)
+	}
+
+	// Without the retitled comm, the pid in TMUX identifies the server
+	server := []model.Process{
+		{PID: 1, Command: "launchd"},
+		{PID: 400, Command: "tmux", Cmdline: "tmux new -s work"},
+		{PID: 401, Command: "zsh", Env: []string{"TMUX=/tmp/tmux-501/default,400,1"}},
+	}
+	src := detectMultiplexer(server)
+	if src == nil || src.Name != "tmux" || src.Details["session"] != "$1" {
+		t.Fatalf("got %+v, want tmux session $1", src)
+	}
+}
+
+func TestDetectMultiplexerScreen(t *testing.T) {
+	ancestry := []model.Process{
+		{PID: 1, Command: "init"},
+		{PID: 300, Command: "screen", User: "bob"},
+		{PID: 301, Command: "bash", Env: []string{"STY=300.backup", "WINDOW=0"}},
+		{PID: 302, Command: "rsync", Env: []string{"STY=300.backup", "WINDOW=0"}},
+	}
+
+	src := detectMultiplexer(ancestry)
+	if src == nil {
+		t.Fatal("expected screen source, got nil")
+	}
+	if src.Name != "screen" {
+		t.Fatalf("Name = %q, want screen", src.Name)
+	}
+	if src.Details["session"] != "backup" {
+		t.Errorf("session = %q, want backup", src.Details["session"])
+	}
+	if src.Details["attach"] != "screen -r 300.backup -p 0" {
+		t.Errorf("attach = %q", src.Details["attach"])
+	}
+}
+
+func TestDetectMultiplexerNone(t *testing.T) {
+	ancestry := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 10, Command: "sshd"},
+		{PID: 11, Command: "bash"},
+	}
+	if src := detectMultiplexer(ancestry); src != nil {
+		t.Fatalf("expected nil, got %+v", src)
+	}
+}
@@ -0,0 +1,101 @@
+package source
+
+import (
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// interpreterPrefixes name runtimes that can serve as a shell when their
+// standard streams are wired to a socket (python -c 'import pty; ...').
+var interpreterPrefixes = []string{"python", "perl", "ruby", "php", "node", "lua", "tclsh"}
+
+// IsShellOrInterpreter reports whether a process is a shell or a scripting
+// runtime, the processes whose stdio is worth checking for sockets.
+func IsShellOrInterpreter(command string) bool {
+	if shells[command] {
+		return true
+	}
+	base := filepath.Base(command)
+	for _, prefix := range interpreterPrefixes {
+		if strings.HasPrefix(base, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// socketActivated reports whether pid runs in a service unit that is handed
+// its connection on stdio; a variable so tests can run without systemd.
+var socketActivated = unitHasSocketStdio
+
+// inetdNames are super-servers that run one program per connection with the
+// socket as its standard streams.
+var inetdNames = map[string]bool{"inetd": true, "xinetd": true}
+
+// NetworkShellWarning reports a shell or interpreter whose standard streams
+// are network sockets: the shape of a reverse or bind shell. ancestry is the
+// chain p belongs to. Programs started by inetd/xinetd or by a systemd
+// socket unit get their connection on stdio by design and are skipped, as
+// are listening sockets: FastCGI hands workers (php-cgi, perl under
+// spawn-fcgi) their listen socket on fd 0.
+func NetworkShellWarning(p model.Process, ancestry []model.Process) (model.Warning, bool) {
+	var sockets []model.StdioSocket
+	for _, s := range p.StdioSockets {
+		if s.State != "LISTEN" {
+			sockets = append(sockets, s)
+		}
+	}
+	if len(sockets) == 0 {
+		return model.Warning{}, false
+	}
+	if i := LiveParentIndex(ancestry, p); i >= 0 && inetdNames[filepath.Base(ancestry[i].Command)] {
+		return model.Warning{}, false
+	}
+	if socketActivated(p.PID) {
+		return model.Warning{}, false
+	}
+
+	var streams, evidence []string
+	remote := ""
+	for _, s := range sockets {
+		streams = append(streams, stdioName(s.FD))
+		local := net.JoinHostPort(s.Address, strconv.Itoa(s.Port))
+		line := "fd " + strconv.Itoa(s.FD) + ": " + s.Protocol + " " + local
+		if s.RemotePort != 0 {
+			peer := net.JoinHostPort(s.RemoteAddress, strconv.Itoa(s.RemotePort))
+			line += " -> " + peer
+			if remote == "" {
+				remote = peer
+			}
+		}
+		evidence = append(evidence, line+" ("+s.State+")")
+	}
+
+	message := p.Command + " (pid " + strconv.Itoa(p.PID) + ") has " + strings.Join(streams, "/") + " attached to a network socket"
+	if remote != "" {
+		message += " connected to " + remote
+	}
+	return model.Warning{
+		ID:       "WITR-NETWORK-SHELL",
+		Severity: model.SeverityCritical,
+		Category: model.CategorySecurity,
+		Message:  message + " (possible reverse shell)",
+		Evidence: evidence,
+	}, true
+}
+
+func stdioName(fd int) string {
+	switch fd {
+	case 0:
+		return "stdin"
+	case 1:
+		return "stdout"
+	case 2:
+		return "stderr"
+	}
+	return "fd " + strconv.Itoa(fd)
+}
@@ -0,0 +1,116 @@
+package source
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestIsShellOrInterpreter(t *testing.T) {
+	for _, cmd := range []string{"bash", "sh", "python3", "python3.12", "perl", "node"} {
+		if !IsShellOrInterpreter(cmd) {
+			t.Errorf("IsShellOrInterpreter(%q) = false", cmd)
+		}
+	}
+	for _, cmd := range []string{"nginx", "sshd", "sleep"} {
+		if IsShellOrInterpreter(cmd) {
+			t.Errorf("IsShellOrInterpreter(%q) = true", cmd)
+		}
+	}
+}
+
+// noSocketUnits makes socketActivated report false for the test.
+func noSocketUnits(t *testing.T) {
+	t.Helper()
+	old := socketActivated
+	socketActivated = func(int) bool { return false }
+	t.Cleanup(func() { socketActivated = old })
+}
+
+func TestNetworkShellWarning(t *testing.T) {
+	noSocketUnits(t)
+	sock := model.Socket{Protocol: "TCP", Address: "10.0.0.5", Port: 51234, RemoteAddress: "203.0.113.9", RemotePort: 4444, State: "ESTABLISHED"}
+	p := model.Process{
+		PID:     812,
+		Command: "bash",
+		StdioSockets: []model.StdioSocket{
+			{FD: 0, Socket: sock},
+			{FD: 1, Socket: sock},
+		},
+	}
+
+	w, ok := NetworkShellWarning(p, []model.Process{p})
+	if !ok {
+		t.Fatal("expected a warning")
+	}
+	if w.ID != "WITR-NETWORK-SHELL" || w.Severity != model.SeverityCritical {
+		t.Errorf("got %s/%s", w.ID, w.Severity)
+	}
+	want := "bash (pid 812) has stdin/stdout attached to a network socket connected to 203.0.113.9:4444 (possible reverse shell)"
+	if w.Message != want {
+		t.Errorf("Message = %q, want %q", w.Message, want)
+	}
+	if !slices.Contains(w.Evidence, "fd 0: TCP 10.0.0.5:51234 -> 203.0.113.9:4444 (ESTABLISHED)") {
+		t.Errorf("Evidence = %v", w.Evidence)
+	}
+
+	if _, ok := NetworkShellWarning(model.Process{Command: "bash"}, nil); ok {
+		t.Error("no sockets should give no warning")
+	}
+
+	// Warnings checks every process in the chain, not just the target
+	chain := []model.Process{p, {PID: 900, Command: "sleep"}}
+	if !slices.Contains(warningMessages(Warnings(chain, Detect(chain))), want) {
+		t.Errorf("chain warning missing: %v", warningMessages(Warnings(chain, Detect(chain))))
+	}
+}
+
+func TestNetworkShellWarningSkipsSuperServers(t *testing.T) {
+	noSocketUnits(t)
+	sock := model.Socket{Protocol: "TCP", Address: "10.0.0.5", Port: 23, RemoteAddress: "203.0.113.9", RemotePort: 51000, State: "ESTABLISHED"}
+	shell := model.Process{
+		PID:          812,
+		PPID:         300,
+		Command:      "sh",
+		StdioSockets: []model.StdioSocket{{FD: 0, Socket: sock}, {FD: 1, Socket: sock}},
+	}
+
+	for _, parent := range []string{"inetd", "xinetd"} {
+		chain := []model.Process{{PID: 1, Command: "systemd"}, {PID: 300, PPID: 1, Command: parent}, shell}
+		if w, ok := NetworkShellWarning(shell, chain); ok {
+			t.Errorf("child of %s warned: %s", parent, w.Message)
+		}
+	}
+
+	// An exited inetd in a reconstructed chain is not the live parent
+	chain := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 300, PPID: 1, Command: "inetd", Exited: true},
+		{PID: 300, PPID: 1, Command: "bash"},
+		shell,
+	}
+	if _, ok := NetworkShellWarning(shell, chain); !ok {
+		t.Error("shell whose live parent is bash should warn")
+	}
+
+	socketActivated = func(pid int) bool { return pid == 812 }
+	if w, ok := NetworkShellWarning(shell, []model.Process{shell}); ok {
+		t.Errorf("socket-activated unit warned: %s", w.Message)
+	}
+}
+
+func TestNetworkShellWarningSkipsListenSockets(t *testing.T) {
+	noSocketUnits(t)
+	// spawn-fcgi -p 9000 -- /usr/bin/php-cgi: the listen socket is fd 0
+	listen := model.Socket{Protocol: "TCP", Address: "127.0.0.1", Port: 9000, State: "LISTEN"}
+	php := model.Process{
+		PID:          4100,
+		PPID:         1,
+		Command:      "php-cgi",
+		StdioSockets: []model.StdioSocket{{FD: 0, Socket: listen}},
+	}
+	if w, ok := NetworkShellWarning(php, []model.Process{{PID: 1, Command: "systemd"}, php}); ok {
+		t.Errorf("FastCGI worker warned: %s", w.Message)
+	}
+}
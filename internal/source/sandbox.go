@@ -0,0 +1,225 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Locations read for sandbox metadata. Variables so tests can use fixtures.
+var (
+	procDir      = "/proc"
+	snapMountDir = "/snap"
+)
+
+// snapUnitRe matches snapd's units: snap.<name>.<app>.service for daemons,
+// snap.<name>.<app>-<uuid|pid>.scope for apps and snap.<name>.hook.<hook>-<id>.scope for hooks.
+// Snap and app names cannot contain dots.
+var snapUnitRe = regexp.MustCompile(`snap\.([a-z0-9][a-z0-9_-]*)\.([A-Za-z0-9][A-Za-z0-9.-]*?)(?:-[0-9]+|-[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})?\.(?:scope|service)`)
+
+// flatpakScopeRe matches app-flatpak-<app-id>-<n>.scope.
+var flatpakScopeRe = regexp.MustCompile(`app-flatpak-(.+?)-[0-9]+\.scope`)
+
+func detectSandbox(ancestry []model.Process) *model.Source {
+	// The closest sandbox wins: a flatpak started from a snapped terminal is a
+	// flatpak. A snap known only from inherited environment is kept as a
+	// fallback in case an ancestor has firmer evidence.
+	var envOnly *model.Source
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		p := ancestry[i]
+		if p.Exited {
+			continue
+		}
+		cgroup := ""
+		if data, err := os.ReadFile(filepath.Join(procDir, itoa(p.PID), "cgroup")); err == nil {
+			cgroup = string(data)
+		}
+		if src := flatpakSource(p, cgroup); src != nil {
+			return src
+		}
+		if src := snapSource(p, cgroup); src != nil {
+			if src.Confidence == 0 {
+				return src
+			}
+			if envOnly == nil {
+				envOnly = src
+			}
+		}
+	}
+	return envOnly
+}
+
+func snapSource(p model.Process, cgroup string) *model.Source {
+	name, app := "", ""
+	corroborated := false
+	var evidence []string
+	if m := snapUnitRe.FindStringSubmatch(cgroup); m != nil {
+		name, app = m[1], m[2]
+		corroborated = true
+		evidence = append(evidence, "cgroup of pid "+itoa(p.PID)+" is in unit "+m[0])
+		if hook, ok := strings.CutPrefix(app, "hook."); ok {
+			app = "hook " + hook
+		}
+	}
+	if name == "" {
+		// Parallel installs set SNAP_INSTANCE_NAME (name_key); SNAP_NAME is the store name
+		name = envValue(p.Env, "SNAP_INSTANCE_NAME")
+		if name == "" {
+			name = envValue(p.Env, "SNAP_NAME")
+		}
+		if name != "" {
+			evidence = append(evidence, "pid "+itoa(p.PID)+" has SNAP_NAME in its environment")
+		}
+	}
+
+	revision := envValue(p.Env, "SNAP_REVISION")
+	exe := p.Cmdline
+	if fields := strings.Fields(exe); len(fields) > 0 {
+		exe = fields[0]
+	}
+	if rest, ok := strings.CutPrefix(exe, snapMountDir+"/"); ok {
+		parts := strings.SplitN(rest, "/", 3)
+		if len(parts) >= 2 {
+			corroborated = true
+			if name == "" {
+				name = parts[0]
+				evidence = append(evidence, "pid "+itoa(p.PID)+" runs "+exe)
+			}
+			if revision == "" {
+				revision = parts[1]
+			}
+		}
+	}
+	if name == "" {
+		return nil
+	}
+	if !corroborated && (strings.HasPrefix(p.Exe, snapMountDir+"/") || strings.HasPrefix(p.Exe, "/snap/")) {
+		corroborated = true
+		evidence = append(evidence, "pid "+itoa(p.PID)+" runs "+p.Exe)
+	}
+
+	details := map[string]string{"package": name}
+	if app != "" {
+		details["app"] = app
+	}
+	if revision != "" {
+		details["revision"] = revision
+	}
+	if confinement := snapConfinement(name, revision); confinement != "" {
+		details["confinement"] = confinement
+	}
+	src := &model.Source{
+		Type:     model.SourceSandbox,
+		Name:     "snap",
+		Details:  details,
+		Evidence: evidence,
+	}
+	if !corroborated {
+		// Anything a snapped app spawns (a terminal, host binaries run by
+		// an IDE) inherits SNAP_*, so without a snap cgroup or executable
+		// this ranks below shells, cron and service managers
+		src.Confidence = 55
+		src.Evidence = append(src.Evidence, "environment only: no snap cgroup or executable under "+snapMountDir)
+	}
+	return src
+}
+
+// snapConfinement reads the confinement mode from the snap's meta/snap.yaml.
+// snapd defaults to strict when the key is absent.
+func snapConfinement(name, revision string) string {
+	if revision == "" {
+		revision = "current"
+	}
+	// Parallel instances share the mount of the store name
+	store, _, _ := strings.Cut(name, "_")
+	data, err := os.ReadFile(filepath.Join(snapMountDir, store, revision, "meta", "snap.yaml"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if v, ok := strings.CutPrefix(line, "confinement:"); ok {
+			return strings.Trim(strings.TrimSpace(v), `"'`)
+		}
+	}
+	return "strict"
+}
+
+func flatpakSource(p model.Process, cgroup string) *model.Source {
+	info := parseFlatpakInfo(filepath.Join(procDir, itoa(p.PID), "root", ".flatpak-info"))
+	appID := info["Application.name"]
+	if appID == "" {
+		appID = info["Runtime.runtime"]
+	}
+	evidence := []string{"/.flatpak-info exists in the root of pid " + itoa(p.PID)}
+	if appID == "" {
+		m := flatpakScopeRe.FindStringSubmatch(cgroup)
+		if m == nil {
+			return nil
+		}
+		appID = m[1]
+		evidence = []string{"cgroup of pid " + itoa(p.PID) + " is in unit " + m[0]}
+	}
+
+	details := map[string]string{"package": appID}
+	if branch := info["Instance.branch"]; branch != "" {
+		details["branch"] = branch
+	}
+	if runtime := info["Application.runtime"]; runtime != "" {
+		details["runtime"] = strings.TrimPrefix(runtime, "runtime/")
+	}
+	if info != nil {
+		details["confinement"] = flatpakConfinement(info)
+	}
+	return &model.Source{
+		Type:     model.SourceSandbox,
+		Name:     "flatpak",
+		Details:  details,
+		Evidence: evidence,
+	}
+}
+
+// flatpakConfinement summarizes how far the sandbox is opened up.
+func flatpakConfinement(info map[string]string) string {
+	var holes []string
+	for _, fs := range strings.Split(info["Context.filesystems"], ";") {
+		switch strings.TrimSuffix(fs, ":rw") {
+		case "host", "host-os", "host-etc", "home":
+			holes = append(holes, "filesystem="+fs)
+		}
+	}
+	if strings.Contains(info["Context.sockets"], "session-bus") {
+		holes = append(holes, "session-bus")
+	}
+	if len(holes) == 0 {
+		return "sandboxed"
+	}
+	return "sandboxed (" + strings.Join(holes, ", ") + ")"
+}
+
+// parseFlatpakInfo reads the keyfile flatpak places at /.flatpak-info in
+// the sandbox, returning "Section.key" entries.
+func parseFlatpakInfo(path string) map[string]string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	info := map[string]string{}
+	section := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line[1 : len(line)-1]
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			info[section+"."+k] = v
+		}
+	}
+	return info
+}
@@ -0,0 +1,143 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDetectSandbox(t *testing.T) {
+	root := t.TempDir()
+	origProc, origSnap := procDir, snapMountDir
+	procDir = filepath.Join(root, "proc")
+	snapMountDir = filepath.Join(root, "snap")
+	t.Cleanup(func() { procDir, snapMountDir = origProc, origSnap })
+
+	writeFile(t, filepath.Join(snapMountDir, "firefox", "4336", "meta", "snap.yaml"), "name: firefox\nversion: 121.0\nconfinement: strict\n")
+	writeFile(t, filepath.Join(snapMountDir, "code", "148", "meta", "snap.yaml"), "name: code\nconfinement: classic\n")
+
+	writeFile(t, filepath.Join(procDir, "2001", "cgroup"), "0::/user.slice/user-1000.slice/user@1000.service/app.slice/snap.firefox.firefox-3c4d9a1e-2f6b-4c1d-9e8a-7b6c5d4e3f21.scope\n")
+	writeFile(t, filepath.Join(procDir, "2002", "cgroup"), "0::/user.slice/user-1000.slice/session-2.scope\n")
+	writeFile(t, filepath.Join(procDir, "2003", "cgroup"), "0::/system.slice/snap.lxd.daemon.service\n")
+	writeFile(t, filepath.Join(procDir, "2004", "cgroup"), "0::/user.slice/user-1000.slice/user@1000.service/app.slice/app-flatpak-org.mozilla.Thunderbird-48213.scope\n")
+	writeFile(t, filepath.Join(procDir, "2004", "root", ".flatpak-info"), `[Application]
+name=org.mozilla.Thunderbird
+runtime=runtime/org.freedesktop.Platform/x86_64/23.08
+
+[Instance]
+instance-id=1397461207
+branch=stable
+arch=x86_64
+flatpak-version=1.14.4
+
+[Context]
+shared=network;ipc;
+sockets=x11;wayland;pulseaudio;
+filesystems=xdg-download;home;
+`)
+	writeFile(t, filepath.Join(procDir, "2005", "cgroup"), "0::/user.slice/user-1000.slice/user@1000.service/app.slice/app-flatpak-com.spotify.Client-9921.scope\n")
+	writeFile(t, filepath.Join(procDir, "2006", "cgroup"), "0::/user.slice/user-1000.slice/session-2.scope\n")
+
+	tests := []struct {
+		name        string
+		ancestry    []model.Process
+		wantName    string
+		wantDetails map[string]string
+	}{
+		{
+			name:        "snap app scope",
+			ancestry:    []model.Process{{PID: 1}, {PID: 2001, Cmdline: "/snap/firefox/4336/usr/lib/firefox/firefox", Env: []string{"SNAP_NAME=firefox", "SNAP_REVISION=4336"}}},
+			wantName:    "snap",
+			wantDetails: map[string]string{"package": "firefox", "app": "firefox", "revision": "4336", "confinement": "strict"},
+		},
+		{
+			name:        "classic snap from env and exe path",
+			ancestry:    []model.Process{{PID: 1}, {PID: 2002, Cmdline: filepath.Join(snapMountDir, "code", "148", "usr/share/code/code") + " --no-sandbox"}},
+			wantName:    "snap",
+			wantDetails: map[string]string{"package": "code", "revision": "148", "confinement": "classic"},
+		},
+		{
+			name:        "snap daemon",
+			ancestry:    []model.Process{{PID: 1}, {PID: 2003, Cmdline: "/bin/sh /snap/lxd/current/commands/daemon.start"}},
+			wantName:    "snap",
+			wantDetails: map[string]string{"package": "lxd", "app": "daemon"},
+		},
+		{
+			name:        "flatpak with info file",
+			ancestry:    []model.Process{{PID: 1}, {PID: 2004, Command: "thunderbird"}},
+			wantName:    "flatpak",
+			wantDetails: map[string]string{"package": "org.mozilla.Thunderbird", "branch": "stable", "runtime": "org.freedesktop.Platform/x86_64/23.08", "confinement": "sandboxed (filesystem=home)"},
+		},
+		{
+			name:        "flatpak from scope only",
+			ancestry:    []model.Process{{PID: 1}, {PID: 2005, Command: "spotify"}, {PID: 2006, Command: "helper"}},
+			wantName:    "flatpak",
+			wantDetails: map[string]string{"package": "com.spotify.Client"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := detectSandbox(tt.ancestry)
+			if src == nil {
+				t.Fatal("detectSandbox() = nil")
+			}
+			if src.Type != model.SourceSandbox || src.Name != tt.wantName {
+				t.Fatalf("got %s/%s, want sandbox/%s", src.Type, src.Name, tt.wantName)
+			}
+			if len(src.Details) != len(tt.wantDetails) {
+				t.Fatalf("Details = %v, want %v", src.Details, tt.wantDetails)
+			}
+			for k, v := range tt.wantDetails {
+				if src.Details[k] != v {
+					t.Errorf("Details[%q] = %q, want %q", k, src.Details[k], v)
+				}
+			}
+		})
+	}
+
+	if src := detectSandbox([]model.Process{{PID: 1}, {PID: 2006, Cmdline: "/usr/bin/bash"}}); src != nil {
+		t.Errorf("detectSandbox() on a plain process = %+v", src)
+	}
+
+	// SNAP_* is inherited by everything a snapped app spawns, so on its own
+	// it ranks below shells
+	envOnly := detectSandbox([]model.Process{{PID: 1}, {PID: 2006, Command: "bash", Exe: "/usr/bin/bash", Env: []string{"SNAP_NAME=code", "SNAP_REVISION=148"}}})
+	if envOnly == nil || envOnly.Confidence != 55 || !slices.ContainsFunc(envOnly.Evidence, func(e string) bool { return strings.HasPrefix(e, "environment only") }) {
+		t.Errorf("env-only snap = %+v, want confidence 55 marked environment only", envOnly)
+	}
+	if envOnly != nil && envOnly.Confidence >= defaultConfidence[model.SourceShell] {
+		t.Errorf("env-only snap confidence %d does not rank below shell", envOnly.Confidence)
+	}
+
+	// The executable under the snap mount corroborates the environment
+	if src := detectSandbox([]model.Process{{PID: 1}, {PID: 2006, Exe: "/snap/code/148/usr/share/code/code", Env: []string{"SNAP_NAME=code"}}}); src == nil || src.Confidence != 0 {
+		t.Errorf("snap exe = %+v, want full sandbox confidence", src)
+	}
+
+	// An ancestor in a snap cgroup beats inherited environment on the target
+	chain := []model.Process{{PID: 1}, {PID: 2001, Command: "firefox"}, {PID: 2006, Command: "bash", Env: []string{"SNAP_NAME=code"}}}
+	if src := detectSandbox(chain); src == nil || src.Details["package"] != "firefox" || src.Confidence != 0 {
+		t.Errorf("cgroup ancestor = %+v, want snap firefox", src)
+	}
+
+	// PID 2005 now belongs to a flatpak; the exited ancestor that once had
+	// it must not be attributed to that sandbox
+	exited := []model.Process{{PID: 1}, {PID: 2005, Command: "make", Exited: true}, {PID: 2006, Cmdline: "/usr/bin/bash"}}
+	if src := detectSandbox(exited); src != nil {
+		t.Errorf("detectSandbox() through an exited ancestor = %+v", src)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,113 @@
+package source
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// privilegedCaps grant root-equivalent power on their own.
+var privilegedCaps = []string{
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_RAWIO",
+	"CAP_DAC_OVERRIDE",
+	"CAP_DAC_READ_SEARCH",
+	"CAP_SETUID",
+	"CAP_SETGID",
+	"CAP_BPF",
+}
+
+// securityWarnings reports privilege and confinement problems of the target.
+func securityWarnings(p model.Process) []model.Warning {
+	sec := p.Security
+	if sec == nil {
+		return nil
+	}
+	var w []model.Warning
+
+	// Capabilities held in a child user namespace (rootless containers,
+	// sandboxes) only apply inside it
+	if p.User != "" && p.User != "root" && p.User != "unknown" && sec.InitUserNamespace {
+		var held []string
+		for _, c := range privilegedCaps {
+			if slices.Contains(sec.CapEffective, c) {
+				held = append(held, c)
+			}
+		}
+		if len(held) > 0 {
+			w = append(w, model.Warning{
+				ID:       "WITR-NONROOT-CAPS",
+				Severity: model.SeverityHigh,
+				Category: model.CategorySecurity,
+				Message:  "Non-root process holds " + strings.Join(held, ", "),
+				Evidence: []string{"user " + p.User + ", effective capabilities: " + strings.Join(sec.CapEffective, ", ")},
+			})
+		}
+	}
+
+	// Unconfined is normal for desktop processes; it matters for processes
+	// that are exposed or expected to be isolated.
+	if sec.Seccomp == "disabled" && unconfined(sec) {
+		var exposure []string
+		if p.Container != "" || p.ContainerInfo != nil {
+			exposure = append(exposure, "runs in a container")
+		}
+		if IsPublicBind(p.BindAddresses) {
+			exposure = append(exposure, "listens on a public interface")
+		}
+		if len(exposure) > 0 {
+			label := "no LSM label"
+			if sec.LSM != "" {
+				label = sec.LSM + " label " + sec.LSMLabel
+			}
+			w = append(w, model.Warning{
+				ID:       "WITR-UNCONFINED",
+				Severity: model.SeverityMedium,
+				Category: model.CategorySecurity,
+				Message:  "Process is unconfined with seccomp disabled",
+				Evidence: []string{label, "seccomp disabled", "process " + strings.Join(exposure, " and ")},
+			})
+		}
+	}
+
+	if sec.Setuid || sec.Setgid {
+		kind := "setuid"
+		if !sec.Setuid {
+			kind = "setgid"
+		}
+		// Package-owned setuid helpers (sudo, passwd) are expected
+		severity := model.SeverityMedium
+		if pkg := p.Package; pkg != nil && pkg.Owned && pkg.Integrity != model.IntegrityModified {
+			severity = model.SeverityLow
+		}
+		evidence := []string{"exe " + p.Exe + " has mode " + sec.ExeMode}
+		if pkg := p.Package; pkg != nil && pkg.Owned {
+			evidence = append(evidence, "installed by package "+pkg.Name+" ("+pkg.Manager+")")
+		}
+		w = append(w, model.Warning{
+			ID:       "WITR-SETUID",
+			Severity: severity,
+			Category: model.CategorySecurity,
+			Message:  "Process runs a " + kind + " binary",
+			Evidence: evidence,
+		})
+	}
+
+	return w
+}
+
+// unconfined reports whether no LSM policy restricts the process.
+func unconfined(sec *model.SecurityInfo) bool {
+	switch {
+	case sec.LSM == "":
+		return true
+	case sec.LSM == "apparmor":
+		return sec.LSMLabel == "unconfined"
+	case sec.LSM == "selinux":
+		return strings.Contains(sec.LSMLabel, ":unconfined_t:") || strings.Contains(sec.LSMLabel, ":spc_t:")
+	}
+	return false
+}
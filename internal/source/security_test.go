@@ -0,0 +1,106 @@
+package source
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestSecurityWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		proc     model.Process
+		want     []string
+		severity model.Severity
+	}{
+		{
+			name: "non-root with CAP_SYS_ADMIN",
+			proc: model.Process{User: "app", Security: &model.SecurityInfo{
+				CapEffective:      []string{"CAP_NET_BIND_SERVICE", "CAP_SYS_ADMIN"},
+				InitUserNamespace: true,
+				Seccomp:           "filter",
+			}},
+			want:     []string{"WITR-NONROOT-CAPS"},
+			severity: model.SeverityHigh,
+		},
+		{
+			name: "rootless container capabilities are namespaced",
+			proc: model.Process{User: "app", Container: "web", Security: &model.SecurityInfo{
+				CapEffective: []string{"CAP_SETUID", "CAP_DAC_OVERRIDE"},
+				Seccomp:      "filter",
+			}},
+		},
+		{
+			name: "unknown user is not non-root",
+			proc: model.Process{User: "unknown", Security: &model.SecurityInfo{
+				CapEffective:      []string{"CAP_SYS_ADMIN"},
+				InitUserNamespace: true,
+				Seccomp:           "filter",
+			}},
+		},
+		{
+			name: "root capabilities are expected",
+			proc: model.Process{User: "root", Security: &model.SecurityInfo{
+				CapEffective: []string{"CAP_SYS_ADMIN"},
+				Seccomp:      "filter",
+			}},
+		},
+		{
+			name: "unconfined container",
+			proc: model.Process{User: "root", Container: "web", Security: &model.SecurityInfo{
+				Seccomp: "disabled", LSM: "apparmor", LSMLabel: "unconfined",
+			}},
+			want:     []string{"WITR-UNCONFINED"},
+			severity: model.SeverityMedium,
+		},
+		{
+			name: "confined container",
+			proc: model.Process{User: "root", Container: "web", Security: &model.SecurityInfo{
+				Seccomp: "disabled", LSM: "apparmor", LSMLabel: "docker-default (enforce)",
+			}},
+		},
+		{
+			name: "unconfined desktop process is not flagged",
+			proc: model.Process{User: "bob", Security: &model.SecurityInfo{Seccomp: "disabled"}},
+		},
+		{
+			name: "package-owned setuid helper",
+			proc: model.Process{
+				User: "root", Exe: "/usr/bin/sudo",
+				Package:  &model.PackageInfo{Manager: "dpkg", Owned: true, Name: "sudo"},
+				Security: &model.SecurityInfo{Setuid: true, ExeMode: "urwxr-xr-x", Seccomp: "filter"},
+			},
+			want:     []string{"WITR-SETUID"},
+			severity: model.SeverityLow,
+		},
+		{
+			name: "unowned setuid binary",
+			proc: model.Process{
+				User: "root", Exe: "/tmp/x",
+				Security: &model.SecurityInfo{Setuid: true, ExeMode: "urwxr-xr-x", Seccomp: "filter"},
+			},
+			want:     []string{"WITR-SETUID"},
+			severity: model.SeverityMedium,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := securityWarnings(tt.proc)
+			var ids []string
+			for _, w := range got {
+				ids = append(ids, w.ID)
+				if w.Severity != tt.severity {
+					t.Errorf("%s severity = %s, want %s", w.ID, w.Severity, tt.severity)
+				}
+				if len(w.Evidence) == 0 {
+					t.Errorf("%s has no evidence", w.ID)
+				}
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Fatalf("warnings = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
@@ -26,6 +26,8 @@ func detectWindowsService(ancestry []model.Process) *model.Source {
 					"manager": "services.exe",
 					"service": p.Service,
 				},
+				Confidence: 90,
+				Evidence:   []string{"pid " + itoa(p.PID) + " is registered as service " + p.Service},
 			}
 		}
 	}
@@ -39,6 +41,8 @@ func detectWindowsService(ancestry []model.Process) *model.Source {
 				Details: map[string]string{
 					"manager": "services.exe",
 				},
+				Confidence: 50,
+				Evidence:   []string{"ancestor " + itoa(p.PID) + " is services.exe"},
 			}
 		}
 	}
@@ -61,6 +65,8 @@ func detectWindowsService(ancestry []model.Process) *model.Source {
 				Details: map[string]string{
 					"manager": "services.exe",
 				},
+				Confidence: 75,
+				Evidence:   []string{"parent " + itoa(parent.PID) + " is services.exe"},
 			}
 		}
 	}
@@ -62,8 +62,10 @@ func detectShell(ancestry []model.Process) *model.Source {
 
 		if shells[cmd] {
 			return &model.Source{
-				Type: model.SourceShell,
-				Name: cmd,
+				Type:       model.SourceShell,
+				Name:       cmd,
+				Confidence: 60,
+				Evidence:   []string{"ancestor " + itoa(ancestry[i].PID) + " is shell " + cmd},
 			}
 		}
 
@@ -77,18 +79,23 @@ func detectShell(ancestry []model.Process) *model.Source {
 			}
 		}
 
+		// Runtimes and editors are weak evidence: services run them too
 		if userTools[lookupName] {
 			return &model.Source{
-				Type: model.SourceShell,
-				Name: base,
+				Type:       model.SourceShell,
+				Name:       base,
+				Confidence: 35,
+				Evidence:   []string{"ancestor " + itoa(ancestry[i].PID) + " is user tool " + base},
 			}
 		}
 
 		// Prefix matches for interpreters with versions or paths
 		if strings.HasPrefix(base, "python") || strings.HasPrefix(base, "node") {
 			return &model.Source{
-				Type: model.SourceShell,
-				Name: base,
+				Type:       model.SourceShell,
+				Name:       base,
+				Confidence: 35,
+				Evidence:   []string{"ancestor " + itoa(ancestry[i].PID) + " is interpreter " + base},
 			}
 		}
 	}
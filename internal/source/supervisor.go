@@ -48,14 +48,19 @@ func detectSupervisor(ancestry []model.Process) *model.Source {
 		}
 	}
 
+	// Init systems sit at the top of almost every chain, so keep looking
+	// for a specific supervisor before settling on a generic one.
+	var generic *model.Source
 	for _, p := range ancestry {
 		// Normalize: remove spaces, lowercase
 		pname := strings.ReplaceAll(strings.ToLower(p.Command), " ", "")
 		pcmd := strings.ReplaceAll(strings.ToLower(p.Cmdline), " ", "")
 		if strings.Contains(pname, "pm2") || strings.Contains(pcmd, "pm2") {
 			return &model.Source{
-				Type: model.SourceSupervisor,
-				Name: "pm2",
+				Type:       model.SourceSupervisor,
+				Name:       "pm2",
+				Confidence: 70,
+				Evidence:   []string{"ancestor " + itoa(p.PID) + " is pm2"},
 			}
 		}
 
@@ -63,12 +68,15 @@ func detectSupervisor(ancestry []model.Process) *model.Source {
 		// Only match if command is exactly "init" or "/sbin/init" etc
 		if p.Command == "init" || strings.HasSuffix(p.Command, "/init") {
 			// Skip "init" if there's a shell in the ancestry
-			if !hasShell {
-				return &model.Source{
-					Type: model.SourceSupervisor,
-					Name: "init",
+			if !hasShell && generic == nil {
+				generic = &model.Source{
+					Type:       model.SourceSupervisor,
+					Name:       "init",
+					Confidence: supervisorConfidence("init"),
+					Evidence:   []string{"ancestor " + itoa(p.PID) + " is " + p.Command},
 				}
 			}
+			continue
 		}
 
 		if label, ok := knownSupervisors[strings.ToLower(p.Command)]; ok {
@@ -77,10 +85,19 @@ func detectSupervisor(ancestry []model.Process) *model.Source {
 			if label == "init" && hasShell {
 				continue
 			}
-			return &model.Source{
-				Type: model.SourceSupervisor,
-				Name: label,
+			src := &model.Source{
+				Type:       model.SourceSupervisor,
+				Name:       label,
+				Confidence: supervisorConfidence(label),
+				Evidence:   []string{"ancestor " + itoa(p.PID) + " is " + p.Command},
+			}
+			if src.Confidence > supervisorConfidence("init") {
+				return src
 			}
+			if generic == nil {
+				generic = src
+			}
+			continue
 		}
 		// Also match on command line for supervisor keywords
 		for sup, label := range knownSupervisors {
@@ -89,12 +106,30 @@ func detectSupervisor(ancestry []model.Process) *model.Source {
 				if label == "init" && hasShell {
 					continue
 				}
-				return &model.Source{
-					Type: model.SourceSupervisor,
-					Name: label,
+				src := &model.Source{
+					Type:       model.SourceSupervisor,
+					Name:       label,
+					Confidence: supervisorConfidence(label),
+					Evidence:   []string{"command line of pid " + itoa(p.PID) + " mentions " + sup},
+				}
+				if src.Confidence > supervisorConfidence("init") {
+					return src
+				}
+				if generic == nil {
+					generic = src
 				}
 			}
 		}
 	}
-	return nil
+	return generic
+}
+
+// supervisorConfidence scores a supervisor label. Init systems and container
+// init shims are on almost every chain and say little about why a process runs.
+func supervisorConfidence(label string) int {
+	switch label {
+	case "init", "systemd service", "launchd", "upstart", "smf", "tini", "docker-init", "podman-init":
+		return 25
+	}
+	return 70
 }
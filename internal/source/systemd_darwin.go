@@ -7,3 +7,7 @@ import "github.com/pranshuparmar/witr/pkg/model"
 func detectSystemd(_ []model.Process) *model.Source {
 	return nil
 }
+
+func unitHasSocketStdio(_ int) bool {
+	return false
+}
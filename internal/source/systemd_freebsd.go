@@ -8,3 +8,7 @@ func detectSystemd(_ []model.Process) *model.Source {
 	// FreeBSD doesn't use systemd
 	return nil
 }
+
+func unitHasSocketStdio(_ int) bool {
+	return false
+}
@@ -6,11 +6,19 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
+// unitOfPID and systemdProperty are variables so tests can run without
+// systemd.
+var (
+	unitOfPID       = getUnitNameFromCgroup
+	systemdProperty = querySystemdProperty
+)
+
 func detectSystemd(ancestry []model.Process) *model.Source {
 	// 1. Check if systemd (PID 1) is in ancestry
 	hasSystemd := false
@@ -27,56 +35,108 @@ func detectSystemd(ancestry []model.Process) *model.Source {
 
 	// 2. Resolve the unit file for the target process (last in user's request chain)
 	targetProc := ancestry[len(ancestry)-1]
-	unitFile := resolveUnitFile(targetProc.PID)
-	description := resolveUnitDescription(targetProc.PID)
+	unit := unitOfPID(targetProc.PID)
+	unitFile := resolveUnitFile(targetProc.PID, unit)
+	description := resolveUnitDescription(targetProc.PID, unit)
+
+	confidence := 20
+	evidence := []string{"pid 1 is " + ancestry[0].Command}
+	if unit != "" {
+		evidence = append(evidence, "cgroup of pid "+itoa(targetProc.PID)+" is in unit "+unit)
+		c, ev := serviceConfidence(ancestry, unit)
+		confidence = max(confidence, c)
+		evidence = append(evidence, ev...)
+	}
 
-	return &model.Source{
+	src := &model.Source{
 		Type:        model.SourceSystemd,
 		Name:        "systemd",
 		Description: description,
 		UnitFile:    unitFile,
+		Confidence:  confidence,
+		Evidence:    evidence,
 	}
+	if unit != "" {
+		src.Details = map[string]string{"unit": unit}
+	}
+	return src
 }
 
-func resolveUnitDescription(pid int) string {
-	if _, err := exec.LookPath("systemctl"); err != nil {
-		return ""
+// serviceConfidence scores a .service unit by how directly it runs the
+// target. Process managers (pm2, supervisord, cron, CI runners) run as
+// services too and their children stay in the manager's cgroup, so only
+// the unit's main process, or a worker it forked of itself, is attributed
+// outright. Another direct child of the main process ranks below every
+// dedicated manager detector; anything deeper keeps the plain rank.
+// Login sessions and app scopes only say the process descends from
+// systemd. Desktop launchers name their units app-*, and shells inside
+// those are interactive.
+func serviceConfidence(ancestry []model.Process, unit string) (int, []string) {
+	if !strings.HasSuffix(unit, ".service") || strings.HasPrefix(unit, "app-") {
+		return 0, nil
+	}
+	mainPID, _ := strconv.Atoi(systemdProperty("MainPID", unit))
+	if mainPID <= 0 {
+		return 0, nil
+	}
+	target := ancestry[len(ancestry)-1]
+	if mainPID == target.PID {
+		return 90, []string{"pid " + itoa(target.PID) + " is the main process of " + unit}
+	}
+	if i := LiveParentIndex(ancestry, target); i >= 0 && ancestry[i].PID == mainPID {
+		parent := ancestry[i]
+		evidence := []string{"parent " + itoa(mainPID) + " is the main process of " + unit}
+		// Workers run the same program as their master (nginx, gunicorn)
+		if parent.Command == target.Command && parent.Exe == target.Exe {
+			return 90, evidence
+		}
+		return 68, evidence
+	}
+	return 0, []string{"main process of " + unit + " is pid " + itoa(mainPID) + ", not the target or its parent"}
+}
+
+// unitHasSocketStdio reports whether pid runs in a service whose standard
+// streams are its socket connection (StandardInput=socket, as used by the
+// per-connection instances of Accept=yes sockets).
+func unitHasSocketStdio(pid int) bool {
+	unit := unitOfPID(pid)
+	if !strings.HasSuffix(unit, ".service") {
+		return false
 	}
+	return systemdProperty("StandardInput", unit) == "socket" || systemdProperty("StandardOutput", unit) == "socket"
+}
 
-	unitName := getUnitNameFromCgroup(pid)
+func resolveUnitDescription(pid int, unitName string) string {
 	if unitName != "" {
-		if desc := querySystemdProperty("Description", unitName); desc != "" {
+		if desc := systemdProperty("Description", unitName); desc != "" {
 			return desc
 		}
 	}
-	if desc := querySystemdProperty("Description", fmt.Sprintf("%d", pid)); desc != "" {
+	if desc := systemdProperty("Description", fmt.Sprintf("%d", pid)); desc != "" {
 		return desc
 	}
 	return ""
 }
 
-func resolveUnitFile(pid int) string {
-	if _, err := exec.LookPath("systemctl"); err != nil {
-		return ""
-	}
-
-	unitName := getUnitNameFromCgroup(pid)
-
+func resolveUnitFile(pid int, unitName string) string {
 	if unitName != "" {
-		if path := querySystemdProperty("FragmentPath", unitName); path != "" {
+		if path := systemdProperty("FragmentPath", unitName); path != "" {
 			return path
 		}
-		if path := querySystemdProperty("SourcePath", unitName); path != "" {
+		if path := systemdProperty("SourcePath", unitName); path != "" {
 			return path
 		}
 	}
-	if path := querySystemdProperty("FragmentPath", fmt.Sprintf("%d", pid)); path != "" {
+	if path := systemdProperty("FragmentPath", fmt.Sprintf("%d", pid)); path != "" {
 		return path
 	}
-	return querySystemdProperty("SourcePath", fmt.Sprintf("%d", pid))
+	return systemdProperty("SourcePath", fmt.Sprintf("%d", pid))
 }
 
 func querySystemdProperty(prop, target string) string {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return ""
+	}
 	cmd := exec.Command("systemctl", "show", "-p", prop, "--value", target)
 	out, err := cmd.Output()
 	if err != nil {
@@ -0,0 +1,171 @@
+//go:build linux
+
+package source
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/pranshuparmar/witr/internal/rules"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// fakeSystemd puts every pid in unit, whose main process is mainPID.
+func fakeSystemd(t *testing.T, unit string, mainPID int) {
+	t.Helper()
+	oldUnit, oldProp := unitOfPID, systemdProperty
+	unitOfPID = func(int) string { return unit }
+	systemdProperty = func(prop, target string) string {
+		if prop == "MainPID" && target == unit {
+			return strconv.Itoa(mainPID)
+		}
+		return ""
+	}
+	t.Cleanup(func() { unitOfPID, systemdProperty = oldUnit, oldProp })
+}
+
+func TestDetectSystemdServiceManagers(t *testing.T) {
+	systemd := model.Process{PID: 1, Command: "systemd"}
+	tests := []struct {
+		name     string
+		unit     string
+		mainPID  int
+		ancestry []model.Process
+		want     model.SourceType
+	}{
+		{
+			name:    "service main process",
+			unit:    "nginx.service",
+			mainPID: 700,
+			ancestry: []model.Process{systemd,
+				{PID: 700, PPID: 1, Command: "nginx", Exe: "/usr/sbin/nginx"}},
+			want: model.SourceSystemd,
+		},
+		{
+			name:    "worker of the main process",
+			unit:    "nginx.service",
+			mainPID: 700,
+			ancestry: []model.Process{systemd,
+				{PID: 700, PPID: 1, Command: "nginx", Exe: "/usr/sbin/nginx"},
+				{PID: 701, PPID: 700, Command: "nginx", Exe: "/usr/sbin/nginx"}},
+			want: model.SourceSystemd,
+		},
+		{
+			name:    "script service beats the shell",
+			unit:    "backup.service",
+			mainPID: 700,
+			ancestry: []model.Process{systemd,
+				{PID: 700, PPID: 1, Command: "bash", Cmdline: "/bin/bash /usr/local/bin/backup.sh"},
+				{PID: 701, PPID: 700, Command: "rsync"}},
+			want: model.SourceSystemd,
+		},
+		{
+			name:    "pm2 app",
+			unit:    "pm2-root.service",
+			mainPID: 700,
+			ancestry: []model.Process{systemd,
+				{PID: 700, PPID: 1, Command: "PM2 v5.3.0: God", Cmdline: "PM2 v5.3.0: God Daemon"},
+				{PID: 701, PPID: 700, Command: "node", Cmdline: "node /srv/app/index.js"}},
+			want: model.SourceSupervisor,
+		},
+		{
+			name:    "supervisord program",
+			unit:    "supervisor.service",
+			mainPID: 700,
+			ancestry: []model.Process{systemd,
+				{PID: 700, PPID: 1, Command: "supervisord", Cmdline: "/usr/bin/python3 /usr/bin/supervisord -n"},
+				{PID: 701, PPID: 700, Command: "celery"}},
+			want: model.SourceSupervisor,
+		},
+		{
+			name:    "cron job",
+			unit:    "cron.service",
+			mainPID: 640,
+			ancestry: []model.Process{systemd,
+				{PID: 640, PPID: 1, Command: "cron"},
+				{PID: 900, PPID: 640, Command: "cron"},
+				{PID: 901, PPID: 900, Command: "sh", Cmdline: "/bin/sh -c /usr/local/bin/backup"},
+				{PID: 902, PPID: 901, Command: "backup"}},
+			want: model.SourceCron,
+		},
+		{
+			name:    "github runner job",
+			unit:    "actions.runner.acme-shop.build-01.service",
+			mainPID: 800,
+			ancestry: []model.Process{systemd,
+				{PID: 800, PPID: 1, Command: "runsvc.sh"},
+				{PID: 810, PPID: 800, Command: "Runner.Listener"},
+				{PID: 820, PPID: 810, Command: "Runner.Worker"},
+				{PID: 830, PPID: 820, Command: "bash", Env: []string{"GITHUB_RUN_ID=1", "GITHUB_JOB=test"}},
+				{PID: 831, PPID: 830, Command: "go", Env: []string{"GITHUB_RUN_ID=1", "GITHUB_JOB=test"}}},
+			want: model.SourceCI,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeSystemd(t, tt.unit, tt.mainPID)
+			got := Detect(tt.ancestry)
+			if got.Type != tt.want {
+				t.Fatalf("Detect = %s/%s (%d), want %s; all: %+v", got.Type, got.Name, got.Confidence, tt.want, DetectAll(tt.ancestry))
+			}
+			// The resolved unit is kept for restart advice
+			if got.Type == model.SourceSystemd && got.Details["unit"] != tt.unit {
+				t.Errorf("Details[unit] = %q, want %q", got.Details["unit"], tt.unit)
+			}
+		})
+	}
+}
+
+func TestDetectSystemdLosesToCustomManager(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	writeFile(t, path, `
+detectors:
+  - name: acme-launcher
+    match: {comm: acme-run}
+    source:
+      type: supervisor
+      name: acme
+`)
+	set, err := rules.Load([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := customRules
+	customRules = func() *rules.Set { return set }
+	t.Cleanup(func() { customRules = old })
+	fakeSystemd(t, "acme.service", 300)
+
+	ancestry := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 300, PPID: 1, Command: "acme-run"},
+		{PID: 301, PPID: 300, Command: "python3"},
+	}
+	if got := Detect(ancestry); got.Name != "acme" {
+		t.Fatalf("Detect = %s/%s (%d), want acme", got.Type, got.Name, got.Confidence)
+	}
+}
+
+func TestUnitHasSocketStdio(t *testing.T) {
+	oldUnit, oldProp := unitOfPID, systemdProperty
+	t.Cleanup(func() { unitOfPID, systemdProperty = oldUnit, oldProp })
+
+	units := map[int]string{
+		10: "telnet@0-10.0.0.5:23-203.0.113.9:51000.service",
+		11: "nginx.service",
+		12: "session-3.scope",
+	}
+	unitOfPID = func(pid int) string { return units[pid] }
+	systemdProperty = func(prop, target string) string {
+		if prop == "StandardInput" && target == units[10] {
+			return "socket"
+		}
+		return "null"
+	}
+
+	for pid, want := range map[int]bool{10: true, 11: false, 12: false, 13: false} {
+		if got := unitHasSocketStdio(pid); got != want {
+			t.Errorf("unitHasSocketStdio(%d) = %v, want %v", pid, got, want)
+		}
+	}
+}
@@ -13,6 +13,9 @@ func termProcess(pid int) error   { return sendSignal(pid, syscall.SIGTERM) }
 func pauseProcess(pid int) error  { return sendSignal(pid, syscall.SIGSTOP) }
 func resumeProcess(pid int) error { return sendSignal(pid, syscall.SIGCONT) }
 
+// reapZombie nudges a zombie's parent to call wait() on its children.
+func reapZombie(ppid int) error { return sendSignal(ppid, syscall.SIGCHLD) }
+
 func sendSignal(pid int, sig syscall.Signal) error {
 	proc, err := os.FindProcess(pid)
 	if err != nil {
@@ -9,3 +9,4 @@ func termProcess(pid int) error    { return fmt.Errorf("not supported on Windows
 func pauseProcess(pid int) error   { return fmt.Errorf("not supported on Windows") }
 func resumeProcess(pid int) error  { return fmt.Errorf("not supported on Windows") }
 func setNice(pid, value int) error { return fmt.Errorf("not supported on Windows") }
+func reapZombie(ppid int) error    { return fmt.Errorf("not supported on Windows") }
@@ -68,9 +68,10 @@ func (m MainModel) fetchTree(p model.Process) tea.Cmd {
 func (m MainModel) fetchProcessDetail(pid int) tea.Cmd {
 	return func() tea.Msg {
 		res, err := pipeline.AnalyzePID(pipeline.AnalyzeConfig{
-			PID:     pid,
-			Verbose: true,
-			Tree:    true,
+			PID:      pid,
+			Verbose:  true,
+			Tree:     true,
+			Packages: true,
 		})
 		if err != nil {
 			return err
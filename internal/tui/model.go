@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pranshuparmar/witr/internal/redact"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -98,6 +99,7 @@ const (
 	actionPause             // SIGSTOP
 	actionResume            // SIGCONT
 	actionRenice            // setpriority
+	actionReap              // SIGCHLD to a zombie's parent
 )
 
 type MainModel struct {
@@ -131,6 +133,9 @@ type MainModel struct {
 	showAllPorts bool
 	version      string
 
+	// redactor masks secrets in command lines and env; nil shows them as is
+	redactor *redact.Redactor
+
 	// Mouse double-click tracking
 	lastClickTime time.Time
 	lastClickX    int
@@ -142,7 +147,7 @@ type MainModel struct {
 	reniceInput    textinput.Model
 }
 
-func InitialModel(version string) MainModel {
+func InitialModel(version string, rd *redact.Redactor) MainModel {
 	columns := []table.Column{
 		{Title: "PID", Width: 8},
 		{Title: "User", Width: 12},
@@ -243,15 +248,18 @@ func InitialModel(version string) MainModel {
 		sortPortCol:     "port",
 		sortPortDesc:    false,
 		version:         version,
+		redactor:        rd,
 	}
 }
 
-func Start(version string) error {
+// Start runs the interactive UI. rd masks secrets in what it shows and may
+// be nil.
+func Start(version string, rd *redact.Redactor) error {
 	if os.Getenv("COLORTERM") == "" {
 		os.Setenv("COLORTERM", "truecolor") //nolint:errcheck
 	}
 
-	p := tea.NewProgram(InitialModel(version), tea.WithAltScreen())
+	p := tea.NewProgram(InitialModel(version, rd), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("error running tui: %w", err)
 	}
@@ -755,6 +755,8 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						execErr = pauseProcess(pid)
 					case actionResume:
 						execErr = resumeProcess(pid)
+					case actionReap:
+						execErr = reapZombie(m.selectedDetail.Zombie.ParentPID)
 					}
 					m.pendingAction = actionNone
 					if execErr != nil {
@@ -768,6 +770,16 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.selectedDetail = nil
 						m.statusMsg = fmt.Sprintf("Signal sent to PID %d", pid)
 						return m, m.refreshProcesses()
+					case actionReap:
+						// The parent decides whether to reap; point at the fallback
+						z := m.selectedDetail.Zombie
+						m.statusMsg = fmt.Sprintf("SIGCHLD sent to PID %d", z.ParentPID)
+						if z.Restart != "" {
+							m.statusMsg += "; if the zombie remains, run " + z.Restart
+						} else {
+							m.statusMsg += "; if the zombie remains, restart the parent"
+						}
+						return m, nil
 					default:
 						// Pause/Resume succeeded — stay in detail view
 						m.statusMsg = "Done"
@@ -794,6 +806,11 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				case "r":
 					m.actionMenuOpen = false
 					m.pendingAction = actionResume
+				case "z":
+					if m.selectedDetail.Zombie != nil {
+						m.actionMenuOpen = false
+						m.pendingAction = actionReap
+					}
 				case "n":
 					m.actionMenuOpen = false
 					m.pendingAction = actionRenice
@@ -962,7 +979,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			fmt.Sscanf(selectedRow[0], "%d", &currentPID)
 		}
 
-		m.processes = msg
+		m.processes = m.redactor.Processes(msg)
 		m.sortProcesses()
 		m.filterProcesses()
 
@@ -1031,12 +1048,13 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			var currentPID int
 			fmt.Sscanf(selected[0], "%d", &currentPID)
 			if model.Result(msg).Process.PID == currentPID {
-				m.updateTreeViewport(model.Result(msg))
+				m.updateTreeViewport(m.redactor.Result(model.Result(msg)))
 			}
 		}
 
 	case model.Result:
-		m.selectedDetail = &msg
+		res := m.redactor.Result(msg)
+		m.selectedDetail = &res
 		m.updateDetailViewport()
 		m.updateEnvViewport()
 
@@ -310,6 +310,8 @@ func (m MainModel) View() string {
 			pid = m.selectedDetail.Process.PID
 		}
 		switch {
+		case m.actionMenuOpen && m.selectedDetail != nil && m.selectedDetail.Zombie != nil:
+			helpText = actionMenuStyle.Render("Esc/q: cancel | Actions:  [z] reap via parent  [k]ill  [t]erm  [p]ause  [r]esume  [n]ice")
 		case m.actionMenuOpen:
 			helpText = actionMenuStyle.Render("Esc/q: cancel | Actions:  [k]ill  [t]erm  [p]ause  [r]esume  [n]ice")
 		case m.pendingAction == actionKill:
@@ -320,6 +322,8 @@ func (m MainModel) View() string {
 			helpText = confirmStyle.Render(fmt.Sprintf("Pause PID %d? [y]es / [n]o", pid))
 		case m.pendingAction == actionResume:
 			helpText = confirmStyle.Render(fmt.Sprintf("Resume PID %d? [y]es / [n]o", pid))
+		case m.pendingAction == actionReap:
+			helpText = confirmStyle.Render(fmt.Sprintf("Send SIGCHLD to parent PID %d so it reaps its zombies? [y]es / [n]o", m.selectedDetail.Zombie.ParentPID))
 		case m.pendingAction == actionRenice:
 			helpText = confirmStyle.Render(fmt.Sprintf("Nice value for PID %d (−20…19): ", pid)) + m.reniceInput.View()
 		case m.statusMsg != "":
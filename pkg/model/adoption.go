@@ -0,0 +1,12 @@
+package model
+
+// AdoptionInfo records that a process outlived its original parent and was
+// reparented to init or a child subreaper.
+type AdoptionInfo struct {
+	AdopterPID     int
+	AdopterCommand string
+	// True when the adopter is a child subreaper rather than PID 1
+	Subreaper bool
+	// Observations that show the reparenting
+	Evidence []string
+}
@@ -0,0 +1,24 @@
+package model
+
+// AuditCheck is the outcome of one system-wide check run by `witr audit`.
+type AuditCheck struct {
+	// ID names the check, e.g. "network-shell"
+	ID          string
+	Description string
+	// Supported is false on platforms where the check cannot run
+	Supported bool
+	// Scanned counts the processes the check inspected
+	Scanned int
+	// Unchecked counts processes the check selected but could not inspect,
+	// e.g. shells in a network namespace whose socket tables were unreadable
+	Unchecked int            `json:",omitempty"`
+	Findings  []AuditFinding `json:",omitempty"`
+}
+
+// AuditFinding is a process flagged by an audit check.
+type AuditFinding struct {
+	Process Process
+	// Ancestry of the flagged process, root first
+	Ancestry []Process `json:",omitempty"`
+	Warning  Warning
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// CaptureInfo describes a process caught at exec time by witr catch.
+type CaptureInfo struct {
+	// How events were observed: "proc connector" or "polling"
+	Method string
+	ExecAt time.Time
+	// Set when the process exited while it was being followed
+	Exited   bool
+	ExitedAt time.Time     `json:",omitzero"`
+	Lifetime time.Duration `json:",omitempty"`
+	// Exit status, when the capture method reports it
+	ExitKnown bool `json:",omitempty"`
+	ExitCode  int  `json:",omitempty"`
+	Signal    int  `json:",omitempty"`
+}
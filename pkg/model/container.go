@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// ContainerInfo describes the container a process runs in, as recorded by
+// the container runtime.
+type ContainerInfo struct {
+	Runtime        string
+	ID             string
+	Name           string
+	Image          string
+	ConfigPath     string    `json:",omitempty"`
+	ComposeProject string    `json:",omitempty"`
+	ComposeService string    `json:",omitempty"`
+	ComposeFiles   string    `json:",omitempty"`
+	RestartPolicy  string    `json:",omitempty"`
+	Ports          []string  `json:",omitempty"`
+	Mounts         []string  `json:",omitempty"`
+	HealthCheck    string    `json:",omitempty"`
+	HealthStatus   string    `json:",omitempty"`
+	RestartCount   int       `json:",omitempty"`
+	StartedAt      time.Time `json:",omitzero"`
+	// Env is the configured environment (image, run and compose), kept
+	// out of JSON output because it often holds secrets
+	Env []string `json:"-"`
+}
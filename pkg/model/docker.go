@@ -8,4 +8,7 @@ type DockerPortMatch struct {
 	Ports          string
 	ComposeProject string
 	ComposeService string
+	RestartPolicy  string
+	Health         string
+	RestartCount   int
 }
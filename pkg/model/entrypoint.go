@@ -0,0 +1,13 @@
+package model
+
+// Entrypoint is what an interpreter or VM process is actually running.
+type Entrypoint struct {
+	// Kind is "jar", "class", "module", "script" or "package-script"
+	Kind string
+	// Target is the jar or script path, main class, module or package script
+	Target string
+	// AppName is a name the application declares, e.g. spring.application.name
+	AppName string `json:",omitempty"`
+	// Shebang is the interpreter line of a script, when readable
+	Shebang string `json:",omitempty"`
+}
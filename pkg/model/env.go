@@ -0,0 +1,29 @@
+package model
+
+// EnvOrigin says where an environment variable of a process came from.
+type EnvOrigin string
+
+const (
+	// EnvInherited variables have the same value in the parent
+	EnvInherited EnvOrigin = "inherited"
+	// EnvLauncher variables were added or changed by the parent that
+	// started the process
+	EnvLauncher EnvOrigin = "launcher"
+	// EnvSource variables are set by the configuration of the source: a
+	// systemd unit, container config or supervisord program
+	EnvSource EnvOrigin = "source"
+	// EnvUnknown is used when the parent's environment cannot be read
+	EnvUnknown EnvOrigin = "unknown"
+)
+
+// EnvVar is one environment variable and its attributed origin.
+type EnvVar struct {
+	Name   string
+	Value  string
+	Origin EnvOrigin
+	// Detail names the parent ("bash (pid 812)") or the configuration
+	// ("systemd EnvironmentFile=/etc/default/app")
+	Detail string `json:",omitempty"`
+	// Changed marks launcher variables that replace an inherited value
+	Changed bool `json:",omitempty"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// ExecutableInfo describes the executable image a process is running.
+type ExecutableInfo struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+	// ModifiedAfterStart is true when the file changed after the process started
+	ModifiedAfterStart bool
+	SHA256             string
+	// ELF details
+	BuildID     string `json:",omitempty"`
+	Interpreter string `json:",omitempty"`
+	Linkage     string `json:",omitempty"` // "static", "static-pie" or "dynamic"
+	// Go build information from debug/buildinfo
+	Go *GoBuildInfo `json:",omitempty"`
+}
+
+// GoBuildInfo holds what the Go toolchain embedded in a binary.
+type GoBuildInfo struct {
+	GoVersion   string
+	Path        string
+	MainModule  string
+	MainVersion string
+	VCSRevision string `json:",omitempty"`
+	VCSTime     string `json:",omitempty"`
+	VCSModified bool   `json:",omitempty"`
+}
@@ -0,0 +1,18 @@
+package model
+
+// KubernetesPod identifies the pod and container a process belongs to.
+// Fields are filled from the node's local kubelet and CRI log state, so they
+// are available without API server access.
+type KubernetesPod struct {
+	Namespace    string
+	Name         string
+	UID          string
+	Container    string
+	ContainerID  string
+	QoSClass     string // Guaranteed, Burstable or BestEffort
+	RestartCount int
+
+	// Owner reference inferred from pod labels (requires crictl)
+	OwnerKind string `json:",omitempty"`
+	OwnerName string `json:",omitempty"`
+}
@@ -0,0 +1,26 @@
+package model
+
+// PackageInfo describes the package that installed an executable.
+type PackageInfo struct {
+	// Manager is the package database consulted ("dpkg", "rpm", "pacman", "apk")
+	Manager string
+	// Owned is false when the package databases were readable but no package lists the file
+	Owned   bool
+	Name    string `json:",omitempty"`
+	Version string `json:",omitempty"`
+	// Origin is the repository the package came from (dpkg, with --verbose
+	// or --json only)
+	Origin string `json:",omitempty"`
+	// Vendor is who built the package, as recorded by rpm
+	Vendor       string `json:",omitempty"`
+	Architecture string `json:",omitempty"`
+	// Integrity of the executable against the package's recorded digest
+	Integrity string `json:",omitempty"`
+}
+
+// Integrity outcomes for PackageInfo.Integrity
+const (
+	IntegrityVerified   = "verified"
+	IntegrityModified   = "modified"
+	IntegrityUnverified = "unverified"
+)
@@ -18,31 +18,65 @@ type Process struct {
 	GitRepo    string
 	GitBranch  string
 	Container  string
+	// Entrypoint of interpreter and VM processes (jar, script, module)
+	Entrypoint *Entrypoint `json:",omitempty"`
 	Service    string
 
+	// Kubernetes pod identity for processes in kubepods cgroups
+	Pod *KubernetesPod `json:",omitempty"`
+	// Container runtime metadata (docker)
+	ContainerInfo *ContainerInfo `json:",omitempty"`
+
 	// Network context
 	ListeningPorts []int
 	BindAddresses  []string
+	// TCP/UDP sockets held by the process (target process only)
+	Connections []Socket `json:",omitempty"`
+	// Network sockets on stdin/stdout/stderr (shells and interpreters only)
+	StdioSockets []StdioSocket `json:",omitempty"`
 
 	// Health status ("healthy", "zombie", "stopped", "high-cpu", "high-mem")
 	Health string
 
-	// Forked status ("forked", "not-forked", "unknown")
+	// True for ancestors reconstructed from process accounting after they exited
+	Exited   bool      `json:",omitempty"`
+	ExitedAt time.Time `json:",omitzero"`
+
+	// True for kernel threads (PF_KTHREAD), which have no executable or userland parent
+	KernelThread bool `json:",omitempty"`
+
+	// Forked status ("forked", "not-forked", "adopted", "daemonized", "unknown")
 	Forked string
+	// Set when the original parent exited and the process was reparented
+	Adoption *AdoptionInfo `json:",omitempty"`
+	// Process group and session IDs (Linux)
+	PGID      int `json:",omitempty"`
+	SessionID int `json:",omitempty"`
+	// True if the process has a controlling terminal (Linux)
+	ControllingTTY bool `json:",omitempty"`
+	// True if SIGHUP is ignored, as under nohup (Linux)
+	IgnoresHangup bool `json:",omitempty"`
 	// Environment variables (key=value)
 	Env []string
 
 	// True if the executable was deleted after the process started
 	ExeDeleted bool
+	// True if the file at Exe is no longer the image the process is running
+	ExeReplaced bool `json:",omitempty"`
+	// Package that installed the executable (target process only)
+	Package *PackageInfo `json:",omitempty"`
+	// Privilege and confinement posture (target process only)
+	Security *SecurityInfo `json:",omitempty"`
 
 	// Extended information for verbose output
-	Memory      MemoryInfo `json:",omitempty"`
-	IO          IOStats    `json:",omitempty"`
-	FileDescs   []string   `json:",omitempty"`
-	FDCount     int        `json:",omitempty"`
-	FDLimit     uint64     `json:",omitempty"`
-	Children    []int      `json:",omitempty"`
-	ThreadCount int        `json:",omitempty"`
+	Executable  *ExecutableInfo `json:",omitempty"`
+	Memory      MemoryInfo      `json:",omitempty"`
+	IO          IOStats         `json:",omitempty"`
+	FileDescs   []string        `json:",omitempty"`
+	FDCount     int             `json:",omitempty"`
+	FDLimit     uint64          `json:",omitempty"`
+	Children    []int           `json:",omitempty"`
+	ThreadCount int             `json:",omitempty"`
 }
 
 // MemoryInfo contains detailed memory information
@@ -8,11 +8,24 @@ type Result struct {
 	Ancestry       []Process
 	Children       []Process `json:",omitempty"`
 	Source         Source
-	Warnings       []string
+	// SourceAlternatives are the other candidate sources, best first
+	SourceAlternatives []Source `json:",omitempty"`
+	Warnings           []Warning
+	// SuppressedWarnings counts warnings silenced by ID or allowlist
+	SuppressedWarnings int `json:",omitempty"`
 
 	// SocketInfo holds socket state details (for port queries)
 	SocketInfo *SocketInfo
 
+	// Capture holds exec/exit details for processes caught by witr catch
+	Capture *CaptureInfo `json:",omitempty"`
+
+	// EnvOrigins attributes each environment variable (--env)
+	EnvOrigins []EnvVar `json:",omitempty"`
+
+	// Zombie explains the unreaped parent when the target is defunct
+	Zombie *ZombieInfo `json:",omitempty"`
+
 	// ResourceContext holds resource usage context (macOS)
 	ResourceContext *ResourceContext
 
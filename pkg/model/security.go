@@ -0,0 +1,32 @@
+package model
+
+// SecurityInfo is the privilege and confinement posture of a process.
+type SecurityInfo struct {
+	// Capability sets decoded to names, e.g. "CAP_NET_BIND_SERVICE" (Linux)
+	CapEffective []string `json:",omitempty"`
+	CapPermitted []string `json:",omitempty"`
+	CapBounding  []string `json:",omitempty"`
+	CapAmbient   []string `json:",omitempty"`
+	// InitUserNamespace is set when the process is in the host's user
+	// namespace, where its capabilities are not confined to a container or
+	// sandbox (Linux)
+	InitUserNamespace bool `json:",omitempty"`
+	// NoNewPrivs blocks privilege gain through setuid binaries and file capabilities
+	NoNewPrivs bool
+	// Seccomp mode: "disabled", "strict" or "filter"
+	Seccomp string `json:",omitempty"`
+	// LSM is the module confining the process ("apparmor", "selinux", "smack")
+	LSM      string `json:",omitempty"`
+	LSMLabel string `json:",omitempty"`
+	// Setuid and Setgid report the mode bits of the executable
+	Setuid bool `json:",omitempty"`
+	Setgid bool `json:",omitempty"`
+	// ExeMode is the permission bits of the executable, e.g. "-rwsr-xr-x"
+	ExeMode string `json:",omitempty"`
+	// ExeWorldWritable and ExeDirWorldWritable are set when any user can
+	// modify the executable or swap it out through its directory
+	ExeWorldWritable    bool `json:",omitempty"`
+	ExeDirWorldWritable bool `json:",omitempty"`
+	// NoexecMount is the noexec mount point the executable lives on (Linux)
+	NoexecMount string `json:",omitempty"`
+}
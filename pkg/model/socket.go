@@ -6,6 +6,21 @@ type Socket struct {
 	Address  string // 0.0.0.0, 127.0.0.1, ::
 	State    string
 	Protocol string
+	// Remote endpoint of connected sockets
+	RemoteAddress string `json:",omitempty"`
+	RemotePort    int    `json:",omitempty"`
+	// Bytes waiting in the kernel: unsent or unacknowledged (tx) and not
+	// yet read by the process (rx). For a listening TCP socket, rx is the
+	// number of connections waiting to be accepted.
+	TxQueue uint64 `json:",omitempty"`
+	RxQueue uint64 `json:",omitempty"`
+}
+
+// StdioSocket is a network socket attached to one of a process's standard
+// streams (fd 0, 1 or 2).
+type StdioSocket struct {
+	FD int
+	Socket
 }
 
 // SocketInfo holds information about a socket's state
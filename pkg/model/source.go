@@ -10,8 +10,12 @@ const (
 	SourceSupervisor     SourceType = "supervisor"
 	SourceCron           SourceType = "cron"
 	SourceShell          SourceType = "shell"
+	SourceMultiplexer    SourceType = "multiplexer"
+	SourceSandbox        SourceType = "sandbox"
+	SourceCI             SourceType = "ci"
 	SourceWindowsService SourceType = "windows_service"
 	SourceInit           SourceType = "init"
+	SourceKernel         SourceType = "kernel"
 	SourceUnknown        SourceType = "unknown"
 )
 
@@ -21,4 +25,8 @@ type Source struct {
 	Description string
 	UnitFile    string
 	Details     map[string]string
+	// Confidence ranks candidates from different detectors (0-100)
+	Confidence int `json:",omitempty"`
+	// Evidence lists the observations behind the attribution
+	Evidence []string `json:",omitempty"`
 }
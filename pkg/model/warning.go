@@ -0,0 +1,34 @@
+package model
+
+// Warning is a non-blocking observation about the target process.
+type Warning struct {
+	// ID is stable across releases, e.g. "WITR-ROOT"; use it to suppress a warning
+	ID       string
+	Severity Severity
+	// Category groups related warnings ("security", "reliability", ...)
+	Category string
+	Message  string
+	// Evidence lists the observations that triggered the warning
+	Evidence []string `json:",omitempty"`
+}
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// Warning categories
+const (
+	CategorySecurity    = "security"
+	CategoryIntegrity   = "integrity"
+	CategoryReliability = "reliability"
+	CategoryResources   = "resources"
+	CategoryNetwork     = "network"
+	CategoryConfig      = "config"
+	CategoryCustom      = "custom"
+)
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// ZombieInfo explains which parent is failing to reap a defunct process.
+type ZombieInfo struct {
+	ParentPID     int
+	ParentCommand string
+	ParentSource  Source
+	// Scheduler state of the parent: running, sleeping, blocked, stopped
+	ParentState string
+	// Kernel function a sleeping or blocked parent waits in
+	ParentWaitChannel string `json:",omitempty"`
+	// Defunct children of the parent, including the target
+	ZombieCount     int
+	OldestZombiePID int
+	// Start time of the oldest zombie. The kernel keeps no exit time, so
+	// this is an upper bound on how long it has been defunct.
+	OldestStartedAt time.Time
+	// Command that restarts the parent's unit or container, when known
+	Restart string `json:",omitempty"`
+	// What will clean the zombies up
+	Remedy string
+}